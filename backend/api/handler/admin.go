@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/api/middleware"
+	"github.com/crazyfrankie/ddd-todolist/backend/api/model/user"
+	"github.com/crazyfrankie/ddd-todolist/backend/application"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
+)
+
+type AdminHandler struct {
+	svc *application.UserService
+}
+
+func NewAdminHandler(svc *application.UserService) *AdminHandler {
+	return &AdminHandler{svc: svc}
+}
+
+func (h *AdminHandler) RegisterRoute(r *gin.RouterGroup) {
+	adminGroup := r.Group("admin", middleware.RequireAuth(), middleware.RequireRole(h.svc.UserRole, entity.RoleAdmin))
+	{
+		adminGroup.GET("users", h.ListUsers())
+		adminGroup.POST("users/:user_id/disable", h.DisableUser())
+		adminGroup.POST("users/:user_id/enable", h.EnableUser())
+		adminGroup.POST("users/:user_id/logout", h.ForceLogout())
+	}
+}
+
+// ListUsers list or search users
+// @router /api/admin/users [GET]
+func (h *AdminHandler) ListUsers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req user.ListUsersRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			invalidParamRequestResponse(c, err.Error())
+			return
+		}
+
+		resp, nextCursor, err := h.svc.ListUsers(c.Request.Context(), &req)
+		if err != nil {
+			errorResponse(c, err)
+			return
+		}
+
+		if nextCursor != 0 {
+			c.Header("X-Next-Cursor", strconv.FormatInt(nextCursor, 10))
+		}
+
+		data(c, resp)
+	}
+}
+
+// DisableUser disable an account and end its sessions
+// @router /api/admin/users/:user_id/disable [POST]
+func (h *AdminHandler) DisableUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := userIDParam(c)
+		if !ok {
+			return
+		}
+
+		err := h.svc.DisableUser(c.Request.Context(), userID)
+		if err != nil {
+			errorResponse(c, err)
+			return
+		}
+
+		success(c)
+	}
+}
+
+// EnableUser enable a disabled account
+// @router /api/admin/users/:user_id/enable [POST]
+func (h *AdminHandler) EnableUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := userIDParam(c)
+		if !ok {
+			return
+		}
+
+		err := h.svc.EnableUser(c.Request.Context(), userID)
+		if err != nil {
+			errorResponse(c, err)
+			return
+		}
+
+		success(c)
+	}
+}
+
+// ForceLogout end every session of a user
+// @router /api/admin/users/:user_id/logout [POST]
+func (h *AdminHandler) ForceLogout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := userIDParam(c)
+		if !ok {
+			return
+		}
+
+		err := h.svc.ForceLogout(c.Request.Context(), userID)
+		if err != nil {
+			errorResponse(c, err)
+			return
+		}
+
+		success(c)
+	}
+}
+
+func userIDParam(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil || userID <= 0 {
+		invalidParamRequestResponse(c, "invalid user_id")
+		return 0, false
+	}
+
+	return userID, true
+}
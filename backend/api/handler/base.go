@@ -9,8 +9,8 @@ func invalidParamRequestResponse(c *gin.Context, errMsg string) {
 	httputil.BadRequest(c, errMsg)
 }
 
-func internalServerErrorResponse(c *gin.Context, err error) {
-	httputil.InternalError(c, err)
+func errorResponse(c *gin.Context, err error) {
+	httputil.Error(c, err)
 }
 
 func success(c *gin.Context) {
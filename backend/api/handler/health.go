@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/health"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+func (h *HealthHandler) RegisterRoute(r *gin.RouterGroup) {
+	r.GET("/healthz", h.Healthz())
+	r.GET("/readyz", h.Readyz())
+}
+
+// Healthz reports that the process is alive, it never looks at dependencies
+// so that an outage of one does not get every instance restarted.
+// @router /healthz [GET]
+func (h *HealthHandler) Healthz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// Readyz reports whether the instance can serve requests.
+// @router /readyz [GET]
+func (h *HealthHandler) Readyz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res := h.checker.Check(c.Request.Context())
+		switch {
+		case res.Draining:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		case !res.Ready:
+			for name, err := range res.Errors {
+				logs.CtxWarnf(c.Request.Context(), "readiness check %s failed, err=%v", name, err)
+			}
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": res.Checks})
+		default:
+			c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": res.Checks})
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/crazyfrankie/ddd-todolist/backend/api/middleware"
 	"github.com/crazyfrankie/ddd-todolist/backend/api/model/task"
 	"github.com/crazyfrankie/ddd-todolist/backend/application"
 )
@@ -18,7 +19,7 @@ func NewTaskHandler(svc *application.TaskService) *TaskHandler {
 }
 
 func (h *TaskHandler) RegisterRoute(r *gin.RouterGroup) {
-	taskGroup := r.Group("tasks")
+	taskGroup := r.Group("tasks", middleware.RequireAuth())
 	{
 		taskGroup.POST("", h.AddTask())
 		taskGroup.GET("/:task_id", h.GetTaskDetail())
@@ -40,7 +41,7 @@ func (h *TaskHandler) AddTask() gin.HandlerFunc {
 
 		resp, err := h.svc.AddTask(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -57,7 +58,7 @@ func (h *TaskHandler) GetTaskDetail() gin.HandlerFunc {
 
 		resp, err := h.svc.GetTaskDetail(c.Request.Context(), taskID)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -65,16 +66,26 @@ func (h *TaskHandler) GetTaskDetail() gin.HandlerFunc {
 	}
 }
 
-// GetTaskList returns tasks list
+// GetTaskList returns tasks list, optionally filtered by creation time and paged by cursor
 // @router /api/tasks [GET]
 func (h *TaskHandler) GetTaskList() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		resp, err := h.svc.GetTaskList(c.Request.Context())
+		var req task.GetTaskListRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			invalidParamRequestResponse(c, err.Error())
+			return
+		}
+
+		resp, nextCursor, err := h.svc.GetTaskList(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
+		if nextCursor != 0 {
+			c.Header("X-Next-Cursor", strconv.FormatInt(nextCursor, 10))
+		}
+
 		data(c, resp)
 	}
 }
@@ -91,7 +102,7 @@ func (h *TaskHandler) UpdateTask() gin.HandlerFunc {
 
 		err := h.svc.UpdateTask(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -108,7 +119,7 @@ func (h *TaskHandler) DeleteTask() gin.HandlerFunc {
 
 		err := h.svc.DeleteTask(c.Request.Context(), taskID)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -2,15 +2,17 @@ package handler
 
 import (
 	"io"
-	"net/http"
-	"strings"
-	"time"
 
+	"github.com/crazyfrankie/frx/errorx"
 	"github.com/gin-gonic/gin"
 
+	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	"github.com/crazyfrankie/ddd-todolist/backend/api/middleware"
 	"github.com/crazyfrankie/ddd-todolist/backend/api/model/user"
 	"github.com/crazyfrankie/ddd-todolist/backend/application"
+	uploadEntity "github.com/crazyfrankie/ddd-todolist/backend/domain/upload/entity"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
 )
 
 type UserHandler struct {
@@ -26,11 +28,12 @@ func (h *UserHandler) RegisterRoute(r *gin.RouterGroup) {
 	{
 		userGroup.POST("register", h.UserRegister())
 		userGroup.POST("login", h.UserLogin())
-		userGroup.GET("logout", h.UserLogout())
-		userGroup.GET("profile", h.GetUserInfo())
-		userGroup.PUT("avatar", h.UpdateUserAvatar())
-		userGroup.PUT("profile", h.UpdateUserProfile())
-		userGroup.POST("reset-password", h.ResetPassword())
+		userGroup.POST("refresh", middleware.CSRF(), h.RefreshToken())
+		userGroup.GET("logout", middleware.RequireAuth(), h.UserLogout())
+		userGroup.GET("profile", middleware.RequireAuth(), h.GetUserInfo())
+		userGroup.PUT("avatar", middleware.RequireAuth(), h.UpdateUserAvatar())
+		userGroup.PUT("profile", middleware.RequireAuth(), h.UpdateUserProfile())
+		userGroup.POST("reset-password", middleware.RequireAuth(), h.ResetPassword())
 	}
 }
 
@@ -46,13 +49,12 @@ func (h *UserHandler) UserRegister() gin.HandlerFunc {
 
 		userInfo, tokens, err := h.svc.UserRegister(c.Request.Context(), c.Request.UserAgent(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
-		c.SetSameSite(http.SameSiteLaxMode)
 		c.Header("x-access-token", tokens[0])
-		c.SetCookie("todolist_refresh", tokens[1], int(time.Hour*24), "/", "", false, true)
+		httputil.SetRefreshToken(c, tokens[1])
 
 		data(c, userInfo)
 	}
@@ -70,29 +72,51 @@ func (h *UserHandler) UserLogin() gin.HandlerFunc {
 
 		userInfo, tokens, err := h.svc.UserLogin(c.Request.Context(), c.Request.UserAgent(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
-		c.SetSameSite(http.SameSiteLaxMode)
 		c.Header("x-access-token", tokens[0])
-		c.SetCookie("todolist_refresh", tokens[1], int(time.Hour*24), "/", "", false, true)
+		httputil.SetRefreshToken(c, tokens[1])
 
 		data(c, userInfo)
 	}
 }
 
+// RefreshToken issues a new access token from the refresh token cookie
+// @router /api/user/refresh [POST]
+func (h *UserHandler) RefreshToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		refresh, err := c.Cookie(httputil.RefreshCookie)
+		if err != nil {
+			errorResponse(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "missing refresh_token in cookie")))
+			return
+		}
+
+		tokens, err := h.svc.RefreshToken(c.Request.Context(), c.Request.UserAgent(), refresh)
+		if err != nil {
+			errorResponse(c, err)
+			return
+		}
+
+		c.Header("x-access-token", tokens[0])
+		httputil.SetRefreshToken(c, tokens[1])
+
+		success(c)
+	}
+}
+
 // UserLogout user logout
 // @router /api/user/logout [GET]
 func (h *UserHandler) UserLogout() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		err := h.svc.UserLogout(c.Request.Context(), c.Request.UserAgent())
 		if err != nil {
-			invalidParamRequestResponse(c, err.Error())
+			errorResponse(c, err)
 			return
 		}
 
-		c.SetCookie("todolist_refresh", "", int(time.Hour*24), "/", "", false, true)
+		httputil.ClearRefreshToken(c)
 
 		success(c)
 	}
@@ -104,7 +128,7 @@ func (h *UserHandler) GetUserInfo() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		resp, err := h.svc.GetUserInfo(c.Request.Context())
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -125,9 +149,9 @@ func (h *UserHandler) UpdateUserAvatar() gin.HandlerFunc {
 			return
 		}
 
-		// Check file type
-		if !strings.HasPrefix(file.Header.Get("Content-Type"), "image/") {
-			invalidParamRequestResponse(c, "invalid file type, only image allowed")
+		// The real format is sniffed from the content later, only the size is checked here
+		if file.Size > uploadEntity.MaxAvatarSize {
+			invalidParamRequestResponse(c, "avatar file too large")
 			return
 		}
 
@@ -139,22 +163,21 @@ func (h *UserHandler) UpdateUserAvatar() gin.HandlerFunc {
 		}
 		defer src.Close()
 
-		fileContent, err := io.ReadAll(src)
+		fileContent, err := io.ReadAll(io.LimitReader(src, uploadEntity.MaxAvatarSize+1))
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
 		req.Avatar = fileContent
-		mimeType := file.Header.Get("Content-Type")
 
-		url, err := h.svc.UpdateUserAvatar(c.Request.Context(), mimeType, &req)
+		resp, err := h.svc.UpdateUserAvatar(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
-		data(c, gin.H{"web_uri": url})
+		data(c, resp)
 	}
 }
 
@@ -170,7 +193,7 @@ func (h *UserHandler) UpdateUserProfile() gin.HandlerFunc {
 
 		err := h.svc.UpdateUserProfile(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -190,7 +213,7 @@ func (h *UserHandler) ResetPassword() gin.HandlerFunc {
 
 		err := h.svc.ResetUserPassword(c.Request.Context(), &req)
 		if err != nil {
-			internalServerErrorResponse(c, err)
+			errorResponse(c, err)
 			return
 		}
 
@@ -0,0 +1,60 @@
+package httputil
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
+)
+
+const (
+	// RefreshCookie holds the refresh token, it is http only so scripts can
+	// not read it.
+	RefreshCookie = "todolist_refresh"
+	// CSRFCookie holds the token that refreshing must echo in CSRFHeader.
+	// Scripts of the frontend can read it, scripts of other sites can not,
+	// so a forged request can not carry it.
+	CSRFCookie = "todolist_csrf"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// SetRefreshToken stores the refresh token and a fresh CSRF token in cookies
+// that live as long as the session.
+func SetRefreshToken(c *gin.Context, token string) {
+	setCookie(c, RefreshCookie, token, consts.SessionMaxAgeSecond, true)
+	setCookie(c, CSRFCookie, newCSRFToken(), consts.SessionMaxAgeSecond, false)
+}
+
+// ClearRefreshToken deletes the refresh and CSRF token cookies.
+func ClearRefreshToken(c *gin.Context) {
+	setCookie(c, RefreshCookie, "", -1, true)
+	setCookie(c, CSRFCookie, "", -1, false)
+}
+
+func setCookie(c *gin.Context, name, value string, maxAge int, httpOnly bool) {
+	cfg := conf.GetConf().Cookie
+	c.SetSameSite(sameSite(cfg.SameSite))
+	c.SetCookie(name, value, maxAge, "/", cfg.Domain, cfg.Secure, httpOnly)
+}
+
+func newCSRFToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b) // never fails
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sameSite(s string) http.SameSite {
+	switch strings.ToLower(s) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	}
+
+	return http.SameSiteLaxMode
+}
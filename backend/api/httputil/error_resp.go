@@ -1,34 +1,112 @@
 package httputil
 
 import (
+	"context"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/crazyfrankie/frx/errorx"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
 )
 
-type data struct {
+// errorBody is the body of every error response. LogID lets a client report
+// a failure in a way that finds its logs.
+type errorBody struct {
 	Code    int32  `json:"code"`
 	Message string `json:"message"`
-	Data    any    `json:"data"`
+	LogID   string `json:"log_id,omitempty"`
+}
+
+// StatusClientClosedRequest is the nginx status for a client that went away
+// before the response, nobody reads it but it keeps them out of the 5xx.
+const StatusClientClosedRequest = 499
+
+// knownErrors are errors of libraries and infra that reach handlers
+// unwrapped. The errno code is used when there is one, the status otherwise.
+var knownErrors = []struct {
+	err    error
+	status int
+	code   int32
+	msg    string
+}{
+	{err: gorm.ErrRecordNotFound, status: http.StatusNotFound, msg: "resource not found"},
+	{err: gorm.ErrDuplicatedKey, status: http.StatusConflict, msg: "resource already exists"},
+	{err: bcrypt.ErrMismatchedHashAndPassword, code: errno.ErrEmailOrPasswordIncorrectCode},
+	{err: lock.ErrNotAcquired, status: http.StatusConflict, msg: "resource is busy, retry later"},
+	{err: context.DeadlineExceeded, status: http.StatusGatewayTimeout, msg: "request timed out"},
+	{err: context.Canceled, status: StatusClientClosedRequest, msg: "client closed request"},
 }
 
 func BadRequest(c *gin.Context, errMsg string) {
-	c.AbortWithStatusJSON(http.StatusBadRequest, data{Code: http.StatusBadRequest, Message: errMsg})
+	abort(c, http.StatusBadRequest, http.StatusBadRequest, errMsg)
 }
 
-func InternalError(c *gin.Context, err error) {
-	var customErr errorx.StatusError
+func Conflict(c *gin.Context, errMsg string) {
+	abort(c, http.StatusConflict, http.StatusConflict, errMsg)
+}
+
+func UnprocessableEntity(c *gin.Context, errMsg string) {
+	abort(c, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity, errMsg)
+}
+
+func RequestEntityTooLarge(c *gin.Context, errMsg string) {
+	abort(c, http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, errMsg)
+}
+
+func TooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	abort(c, http.StatusTooManyRequests, http.StatusTooManyRequests, "too many requests")
+}
 
+// Error reports err with the status its errno code or kind maps to, anything
+// unknown is an internal error whose details are only logged.
+func Error(c *gin.Context, err error) {
+	ctx := c.Request.Context()
+
+	var customErr errorx.StatusError
 	if errors.As(err, &customErr) && customErr.Code() != 0 {
-		logs.CtxWarnf(c.Request.Context(), "[ErrorX] error:  %v %v \n", customErr.Code(), err)
-		c.AbortWithStatusJSON(http.StatusOK, data{Code: customErr.Code(), Message: customErr.Msg()})
+		logs.CtxWarnf(ctx, "[ErrorX] error:  %v %v \n", customErr.Code(), err)
+		abort(c, codeStatus(customErr.Code()), customErr.Code(), localize(c, customErr.Code(), customErr.Msg()))
+		return
+	}
+
+	for _, known := range knownErrors {
+		if !errors.Is(err, known.err) {
+			continue
+		}
+		if known.code != 0 {
+			Error(c, errorx.WrapByCode(err, known.code))
+			return
+		}
+		logs.CtxWarnf(ctx, "[KnownError] error: %v \n", err)
+		abort(c, known.status, int32(known.status), known.msg)
 		return
 	}
 
-	logs.CtxErrorf(c.Request.Context(), "[InternalError]  error: %v \n", err)
-	c.AbortWithStatusJSON(http.StatusInternalServerError, data{Code: 500, Message: "internal server error"})
+	logs.CtxErrorf(ctx, "[InternalError]  error: %v \n", err)
+	abort(c, http.StatusInternalServerError, http.StatusInternalServerError, "internal server error")
+}
+
+// codeStatus returns the http_status of code in its errno catalog, codes
+// without one are client errors reported as 400.
+func codeStatus(code int32) int {
+	if status, ok := errno.HTTPStatus(code); ok {
+		return status
+	}
+
+	return http.StatusBadRequest
+}
+
+func abort(c *gin.Context, status int, code int32, msg string) {
+	logID, _ := logs.LogIDFromCtx(c.Request.Context())
+	c.AbortWithStatusJSON(status, errorBody{Code: code, Message: msg, LogID: logID})
 }
@@ -0,0 +1,198 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/crazyfrankie/frx/errorx"
+	"github.com/crazyfrankie/frx/errorx/code"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
+)
+
+// unlistedCode has no http_status in any catalog
+const unlistedCode = 199999
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	code.Register(unlistedCode, "unlisted")
+}
+
+func serve(t *testing.T, logID string, handle func(c *gin.Context)) (*httptest.ResponseRecorder, errorBody) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if logID != "" {
+		c.Request = c.Request.WithContext(logs.WithLogID(c.Request.Context(), logID))
+	}
+	handle(c)
+
+	var body errorBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	return w, body
+}
+
+func TestErrorErrnoStatus(t *testing.T) {
+	tests := []struct {
+		code   int32
+		status int
+	}{
+		{errno.ErrUserNotFoundCode, http.StatusNotFound},
+		{errno.ErrEmailExistCode, http.StatusConflict},
+		{errno.ErrUniqueNameExistCode, http.StatusConflict},
+		{errno.ErrAuthFailedCode, http.StatusUnauthorized},
+		{errno.ErrEmailOrPasswordIncorrectCode, http.StatusUnauthorized},
+		{errno.ErrAvatarInvalidCode, http.StatusBadRequest},
+		{errno.ErrAvatarTooLargeCode, http.StatusRequestEntityTooLarge},
+		{errno.ErrRegisterInProgressCode, http.StatusConflict},
+		{errno.ErrCSRFCheckFailedCode, http.StatusForbidden},
+		{errno.ErrAccountDisabledCode, http.StatusForbidden},
+		{errno.ErrPermissionDeniedCode, http.StatusForbidden},
+		{errno.ErrDisableSelfCode, http.StatusBadRequest},
+		{errno.ErrTaskNotFoundCode, http.StatusNotFound},
+		{errno.ErrTaskPriorityInvalidCode, http.StatusBadRequest},
+		{unlistedCode, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.code), func(t *testing.T) {
+			w, body := serve(t, "", func(c *gin.Context) {
+				Error(c, fmt.Errorf("wrapped: %w", errorx.New(tt.code)))
+			})
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if body.Code != tt.code {
+				t.Errorf("code = %d, want %d", body.Code, tt.code)
+			}
+			if body.Message == "" {
+				t.Error("message is empty")
+			}
+		})
+	}
+}
+
+func TestErrorKnownErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   int32
+	}{
+		{"record not found", gorm.ErrRecordNotFound, http.StatusNotFound, http.StatusNotFound},
+		{"duplicated key", gorm.ErrDuplicatedKey, http.StatusConflict, http.StatusConflict},
+		{"password mismatch", bcrypt.ErrMismatchedHashAndPassword, http.StatusUnauthorized, errno.ErrEmailOrPasswordIncorrectCode},
+		{"lock not acquired", lock.ErrNotAcquired, http.StatusConflict, http.StatusConflict},
+		{"deadline exceeded", context.DeadlineExceeded, http.StatusGatewayTimeout, http.StatusGatewayTimeout},
+		{"canceled", context.Canceled, StatusClientClosedRequest, StatusClientClosedRequest},
+		{"unknown", errors.New("dial tcp 10.0.0.1:3306: connection refused"), http.StatusInternalServerError, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := serve(t, "", func(c *gin.Context) {
+				Error(c, fmt.Errorf("query user: %w", tt.err))
+			})
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if body.Code != tt.code {
+				t.Errorf("code = %d, want %d", body.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestErrorHidesInternalDetails(t *testing.T) {
+	_, body := serve(t, "", func(c *gin.Context) {
+		Error(c, errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	})
+
+	if body.Message != "internal server error" {
+		t.Errorf("message = %q, want a generic one", body.Message)
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	w, body := serve(t, "", func(c *gin.Context) {
+		TooManyRequests(c, 1500*time.Millisecond)
+	})
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+	if body.Code != http.StatusTooManyRequests {
+		t.Errorf("code = %d, want %d", body.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestErrorBodyShape(t *testing.T) {
+	tests := []struct {
+		name  string
+		logID string
+		keys  []string
+	}{
+		{"with log id", "log-1", []string{"code", "message", "log_id"}},
+		{"without log id", "", []string{"code", "message"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := serve(t, tt.logID, func(c *gin.Context) {
+				Error(c, errorx.New(errno.ErrUserNotFoundCode))
+			})
+
+			var raw map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+				t.Fatal(err)
+			}
+			if len(raw) != len(tt.keys) {
+				t.Errorf("body = %s, want exactly the keys %v", w.Body.String(), tt.keys)
+			}
+			for _, k := range tt.keys {
+				if _, ok := raw[k]; !ok {
+					t.Errorf("body = %s, missing %q", w.Body.String(), k)
+				}
+			}
+			if body.LogID != tt.logID {
+				t.Errorf("log_id = %q, want %q", body.LogID, tt.logID)
+			}
+		})
+	}
+}
+
+func TestErrorLocalizedVary(t *testing.T) {
+	w, body := serve(t, "", func(c *gin.Context) {
+		c.Request.Header.Set("Accept-Language", "zh-CN")
+		c.Writer.Header().Add("Vary", "Origin")
+		Error(c, errorx.New(errno.ErrTaskNotFoundCode))
+	})
+
+	if got := w.Header().Values("Vary"); !slices.Equal(got, []string{"Origin", "Accept-Language", "Cookie"}) {
+		t.Errorf("Vary = %v, want Origin kept and Accept-Language, Cookie added", got)
+	}
+	if got := w.Header().Get("Content-Language"); got != "zh" {
+		t.Errorf("Content-Language = %q, want %q", got, "zh")
+	}
+	if body.Message != "任务不存在" {
+		t.Errorf("message = %q, want the zh one", body.Message)
+	}
+}
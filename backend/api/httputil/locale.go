@@ -0,0 +1,71 @@
+package httputil
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
+)
+
+// LocaleCookie holds the locale the user picked, it wins over the
+// Accept-Language header of the browser.
+const LocaleCookie = "todolist_locale"
+
+var (
+	matcherOnce sync.Once
+	locales     []string
+	matcher     language.Matcher
+)
+
+// locale picks the locale error messages are rendered in from those they
+// are translated to, falling back to errno.DefaultLocale.
+func locale(c *gin.Context) string {
+	matcherOnce.Do(func() {
+		locales = errno.Locales()
+		tags := make([]language.Tag, 0, len(locales))
+		for _, l := range locales {
+			tags = append(tags, language.Make(l))
+		}
+		matcher = language.NewMatcher(tags)
+	})
+
+	var prefs []language.Tag
+	if pref, err := c.Cookie(LocaleCookie); err == nil {
+		if tag, err := language.Parse(pref); err == nil {
+			prefs = append(prefs, tag)
+		}
+	}
+	if accept, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language")); err == nil {
+		prefs = append(prefs, accept...)
+	}
+	if len(prefs) == 0 {
+		return errno.DefaultLocale
+	}
+
+	_, idx, confidence := matcher.Match(prefs...)
+	if confidence == language.No {
+		return errno.DefaultLocale
+	}
+
+	return locales[idx]
+}
+
+// localize returns msg, the message of code, in the locale of the request.
+func localize(c *gin.Context, code int32, msg string) string {
+	// Added rather than set, CORS has already put Origin in Vary
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Writer.Header().Add("Vary", "Cookie")
+
+	l := locale(c)
+	if l == errno.DefaultLocale {
+		return msg
+	}
+	if localized, ok := errno.LocalizedMessage(code, l); ok {
+		c.Header("Content-Language", l)
+		return localized
+	}
+
+	return msg
+}
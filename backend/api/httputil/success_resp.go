@@ -6,6 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type data struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data"`
+}
+
 func Success(c *gin.Context, resp any) {
 	c.JSON(http.StatusOK, data{Data: resp})
 }
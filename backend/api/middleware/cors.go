@@ -1,19 +1,45 @@
 package middleware
 
 import (
-	"time"
+	"reflect"
+	"sync/atomic"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
 )
 
-func CORS() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3001"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "x-access-token"},
+// CORS answers preflight requests and allows the configured origins, reloaded
+// cors settings apply to the requests that follow.
+func CORS(c conf.CORS) gin.HandlerFunc {
+	var handler atomic.Pointer[gin.HandlerFunc]
+	handler.Store(newCORS(c))
+	conf.Subscribe(func(prev, cur *conf.Config) {
+		if !reflect.DeepEqual(prev.CORS, cur.CORS) {
+			handler.Store(newCORS(cur.CORS))
+			logs.Infof("cors reloaded, allowed origins %v", cur.CORS.AllowOrigins)
+		}
+	})
+
+	return func(c *gin.Context) {
+		(*handler.Load())(c)
+	}
+}
+
+func newCORS(c conf.CORS) *gin.HandlerFunc {
+	h := cors.New(cors.Config{
+		AllowOrigins: c.AllowOrigins,
+		AllowMethods: c.AllowMethods,
+		AllowHeaders: c.AllowHeaders,
+		ExposeHeaders: []string{
+			"Content-Length", "x-access-token", "X-Next-Cursor", "Idempotent-Replayed", "X-Log-Id",
+			"Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+		},
 		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+		MaxAge:           c.MaxAge,
 	})
+
+	return &h
 }
@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	uploadEntity "github.com/crazyfrankie/ddd-todolist/backend/domain/upload/entity"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ctxcache"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLen      = 255
+
+	// idempotencyTTL is how long a response is kept for replay.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyInFlightTTL bounds how long a crashed request blocks its key,
+	// a running one keeps it locked for as long as it takes.
+	idempotencyInFlightTTL = time.Minute
+	// maxIdempotentBodySize bounds the body buffered for the fingerprint, it
+	// leaves room for the multipart overhead of an avatar upload.
+	maxIdempotentBodySize = uploadEntity.MaxAvatarSize + 1<<20
+)
+
+type idempotentRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotency makes authenticated writes carrying an Idempotency-Key safe to
+// retry. The first request with a key runs and its response is stored, retries
+// with the same key and payload get that response replayed instead of running
+// again. The key is locked while the first request runs, concurrent retries
+// get a conflict. Only final responses are stored, see finalStatus, anything
+// else leaves the key free to be retried.
+//
+// It must run after authentication, keys are scoped to the user.
+func Idempotency(c cache.Cache, locker lock.Locker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeader)
+		if key == "" || !isWriteMethod(ctx.Request.Method) {
+			ctx.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			httputil.BadRequest(ctx, "Idempotency-Key is too long")
+			return
+		}
+
+		uid, ok := ctxcache.Get[int64](ctx.Request.Context(), consts.SessionDataKeyInCtx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		fingerprint, err := requestFingerprint(ctx)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			httputil.RequestEntityTooLarge(ctx, fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		if err != nil {
+			httputil.BadRequest(ctx, err.Error())
+			return
+		}
+
+		reqCtx := ctx.Request.Context()
+		// the key is released or stored even when the client went away
+		storeCtx := context.WithoutCancel(reqCtx)
+		cacheKey := fmt.Sprintf("idempotency:%d:%s", uid, key)
+
+		if replayIdempotent(ctx, c, cacheKey, fingerprint) {
+			return
+		}
+
+		l, err := locker.TryLock(reqCtx, cacheKey, idempotencyInFlightTTL)
+		if errors.Is(err, lock.ErrNotAcquired) {
+			// the holder may have stored its response since the lookup
+			if !replayIdempotent(ctx, c, cacheKey, fingerprint) {
+				httputil.Conflict(ctx, "a request with this Idempotency-Key is in progress")
+			}
+			return
+		}
+		if err != nil {
+			logs.CtxWarnf(reqCtx, "lock idempotency key failed, err=%v", err)
+			ctx.Next()
+			return
+		}
+		// released on panics too, so that a retry is not locked out
+		defer func() {
+			if err := l.Release(storeCtx); err != nil {
+				logs.CtxWarnf(reqCtx, "release idempotency key failed, err=%v", err)
+			}
+		}()
+
+		// the previous holder may have finished between the lookup and the lock
+		if replayIdempotent(ctx, c, cacheKey, fingerprint) {
+			return
+		}
+
+		stop := lock.KeepAlive(storeCtx, l, idempotencyInFlightTTL)
+		defer stop()
+
+		rec := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = rec
+		ctx.Next()
+
+		status := rec.Status()
+		if !finalStatus(status) {
+			return
+		}
+
+		done, _ := json.Marshal(&idempotentRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		})
+		if err := c.Set(storeCtx, cacheKey, string(done), idempotencyTTL); err != nil {
+			logs.CtxWarnf(reqCtx, "store idempotent response failed, err=%v", err)
+		}
+	}
+}
+
+// finalStatus reports whether a response is the outcome of the request and
+// is replayed for retries. Server errors and the client errors that depend on
+// the moment, a conflict, throttling or a timeout, are worth retrying.
+func finalStatus(status int) bool {
+	switch {
+	case status >= http.StatusOK && status < http.StatusMultipleChoices:
+		return true
+	case status < http.StatusBadRequest || status >= http.StatusInternalServerError:
+		return false
+	}
+
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests, httputil.StatusClientClosedRequest:
+		return false
+	}
+
+	return true
+}
+
+// replayIdempotent answers a request whose key has a stored response. It
+// returns false without responding when there is none.
+func replayIdempotent(ctx *gin.Context, c cache.Cache, cacheKey, fingerprint string) bool {
+	val, err := c.Get(ctx.Request.Context(), cacheKey)
+	if errors.Is(err, cache.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		httputil.Error(ctx, err)
+		return true
+	}
+
+	var record idempotentRecord
+	if err := json.Unmarshal([]byte(val), &record); err != nil {
+		httputil.Error(ctx, err)
+		return true
+	}
+
+	if record.Fingerprint != fingerprint {
+		httputil.UnprocessableEntity(ctx, "Idempotency-Key was used for a different request")
+		return true
+	}
+
+	ctx.Header(idempotencyReplayedHeader, "true")
+	ctx.Data(record.Status, record.ContentType, record.Body)
+	ctx.Abort()
+
+	return true
+}
+
+// requestFingerprint identifies the request a key was first used for, it
+// leaves the body readable for the handler. Bodies over maxIdempotentBodySize
+// fail with an *http.MaxBytesError.
+func requestFingerprint(c *gin.Context) (string, error) {
+	r := c.Request
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, r.Body, maxIdempotentBodySize))
+	if err != nil {
+		return "", fmt.Errorf("read body failed: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,17 +1,79 @@
 package middleware
 
 import (
-	"context"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+const (
+	logIDHeader    = "X-Log-Id"
+	maxLogIDLength = 64
 )
 
+// SetLogID tags the request context with a log ID and echoes it in the
+// X-Log-Id response header. A well formed X-Log-Id from an upstream proxy is
+// kept so that its logs and ours correlate.
 func SetLogID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logID := uuid.New().String()
-		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "log-id", logID))
+		logID := c.GetHeader(logIDHeader)
+		if !validLogID(logID) {
+			logID = uuid.New().String()
+		}
+		c.Request = c.Request.WithContext(logs.WithLogID(c.Request.Context(), logID))
+		c.Header(logIDHeader, logID)
+
+		c.Next()
+	}
+}
+
+// AccessLog logs every request once it has been handled, at warn level for
+// client errors and error level for server errors. It must run after SetLogID.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
 
 		c.Next()
+
+		status := c.Writer.Status()
+		ctx := logs.WithFields(c.Request.Context(),
+			"method", c.Request.Method,
+			"path", path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"size", c.Writer.Size(),
+		)
+		if len(c.Errors) > 0 {
+			ctx = logs.WithFields(ctx, "errors", c.Errors.String())
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			logs.CtxErrorf(ctx, "access")
+		case status >= http.StatusBadRequest:
+			logs.CtxWarnf(ctx, "access")
+		default:
+			logs.CtxInfof(ctx, "access")
+		}
+	}
+}
+
+func validLogID(logID string) bool {
+	if logID == "" || len(logID) > maxLogIDLength {
+		return false
 	}
+	for _, r := range logID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
 }
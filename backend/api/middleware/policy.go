@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/crazyfrankie/frx/errorx"
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
+)
+
+// RoleResolver returns the role of a user.
+type RoleResolver func(ctx context.Context, uid int64) (entity.Role, error)
+
+// RequireRole admits sessions whose user holds one of roles, it is attached to
+// a route group after RequireAuth. The role is resolved on every request
+// rather than carried in the token, so that a demotion takes effect at once.
+func RequireRole(resolve RoleResolver, roles ...entity.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		uid, ok := ctxutil.GetUIDFromCtx(ctx)
+		if !ok {
+			httputil.Error(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "RequireRole needs RequireAuth to run first")))
+			return
+		}
+
+		role, err := resolve(ctx, uid)
+		if err != nil {
+			httputil.Error(c, err)
+			return
+		}
+		if !slices.Contains(roles, role) {
+			httputil.Error(c, errorx.New(errno.ErrPermissionDeniedCode, errorx.KV("reason", fmt.Sprintf("role %q is not one of %v", role, roles))))
+			return
+		}
+
+		c.Next()
+	}
+}
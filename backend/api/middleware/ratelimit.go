@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/ratelimit"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ctxcache"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
+)
+
+// RateLimit throttles each request by the policy with the longest path prefix
+// matching it on a segment boundary, requests matching no policy are not limited. Policies keyed by
+// user need the session, so it must run after authentication. Reloaded
+// rateLimit settings apply to the requests that follow.
+//
+// The limiter failing lets requests through rather than taking the API down.
+func RateLimit(limiter ratelimit.Limiter, c conf.RateLimit) gin.HandlerFunc {
+	var table atomic.Pointer[[]conf.RatePolicy]
+	table.Store(ratePolicies(c))
+	conf.Subscribe(func(prev, cur *conf.Config) {
+		if !reflect.DeepEqual(prev.RateLimit, cur.RateLimit) {
+			table.Store(ratePolicies(cur.RateLimit))
+			logs.Infof("rate limit reloaded, enabled=%v, %d policies", cur.RateLimit.Enabled, len(cur.RateLimit.Policies))
+		}
+	})
+
+	return func(c *gin.Context) {
+		policies := *table.Load()
+		idx := slices.IndexFunc(policies, func(p conf.RatePolicy) bool {
+			return matchPathPrefix(c.Request.URL.Path, p.Prefix)
+		})
+		if idx < 0 {
+			c.Next()
+			return
+		}
+		p := policies[idx]
+
+		ctx := c.Request.Context()
+		res, err := limiter.Allow(ctx, p.Prefix+":"+rateLimitSubject(c, p.By), ratelimit.Limit{
+			Rate:   p.Rate,
+			Period: p.Period,
+			Burst:  p.Burst,
+		})
+		if err != nil {
+			logs.CtxWarnf(ctx, "rate limit %s failed, err=%v", p.Prefix, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			httputil.TooManyRequests(c, res.RetryAfter)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ratePolicies orders the usable policies longest prefix first, none when
+// rate limiting is disabled.
+func ratePolicies(c conf.RateLimit) *[]conf.RatePolicy {
+	valid := make([]conf.RatePolicy, 0, len(c.Policies))
+	if !c.Enabled {
+		return &valid
+	}
+
+	for _, p := range c.Policies {
+		if p.Rate <= 0 || p.Period <= 0 {
+			logs.Warnf("ignore rate limit policy for %q, rate and period must be positive", p.Prefix)
+			continue
+		}
+		valid = append(valid, p)
+	}
+	slices.SortStableFunc(valid, func(a, b conf.RatePolicy) int {
+		return len(b.Prefix) - len(a.Prefix)
+	})
+
+	return &valid
+}
+
+// matchPathPrefix reports whether prefix covers path on a segment boundary,
+// /api/user/login covers /api/user/login/ but not /api/user/loginx.
+func matchPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+func rateLimitSubject(c *gin.Context, by string) string {
+	if by == "user" {
+		if uid, ok := ctxcache.Get[int64](c.Request.Context(), consts.SessionDataKeyInCtx); ok {
+			return "user:" + strconv.FormatInt(uid, 10)
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
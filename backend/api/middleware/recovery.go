@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/crazyfrankie/frx/errorx"
+	"github.com/gin-gonic/gin"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
+)
+
+// Recovery turns panics into error responses. A handler reached without a
+// session, which panics with ctxutil.ErrNoSession, is an authentication
+// failure. Anything else is logged with its stack under the request's log ID
+// and reported as an internal error. It must run after SetLogID.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			ctx := c.Request.Context()
+
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			if errors.Is(err, ctxutil.ErrNoSession) {
+				logs.CtxWarnf(ctx, "[Recovery] %s %s reached without a session, declare it with RequireAuth", c.Request.Method, c.FullPath())
+				httputil.Error(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "no session")))
+				return
+			}
+			if brokenPipe(err) {
+				// nothing can be written to the client anymore
+				logs.CtxWarnf(ctx, "[Recovery] connection closed by client: %v", err)
+				c.Abort()
+				return
+			}
+
+			err = fmt.Errorf("[Recovery] panic: %w\n%s", err, debug.Stack())
+			if c.Writer.Written() {
+				logs.CtxErrorf(ctx, "%v", err)
+				c.Abort()
+				return
+			}
+			httputil.Error(c, err)
+		}()
+
+		c.Next()
+	}
+}
+
+func brokenPipe(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+
+	var sysErr *os.SyscallError
+	return errors.As(opErr, &sysErr) && (errors.Is(sysErr, syscall.EPIPE) || errors.Is(sysErr, syscall.ECONNRESET))
+}
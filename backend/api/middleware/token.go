@@ -1,68 +1,97 @@
 package middleware
 
 import (
-	"net/http"
-	"time"
+	"reflect"
+	"runtime"
+	"slices"
 
 	"github.com/crazyfrankie/frx/errorx"
 	"github.com/gin-gonic/gin"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/api/httputil"
+	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/token"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ctxcache"
 	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
 	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
 )
 
+const unauthenticatedKey = "unauthenticated"
+
+// requireAuthName is the name RequireAuth has in a route's handler chain.
+var requireAuthName = runtime.FuncForPC(reflect.ValueOf(RequireAuth()).Pointer()).Name()
+
 type AuthnHandler struct {
-	ignore map[string]struct{}
-	token  token.JWT
+	token token.JWT
 }
 
 func NewAuthnHandler(token token.JWT) *AuthnHandler {
-	return &AuthnHandler{token: token, ignore: make(map[string]struct{})}
-}
-
-func (h *AuthnHandler) IgnorePath(path string) *AuthnHandler {
-	h.ignore[path] = struct{}{}
-	return h
+	return &AuthnHandler{token: token}
 }
 
+// JWTAuthMW resolves the user of requests carrying a valid access token. It
+// never rejects a request, routes declare that they need a session with
+// RequireAuth and get the reason there is none from it.
+//
+// With jwt.silentRefresh an expired access token of a route requiring a
+// session is refreshed here, so that the middlewares keyed by user that follow
+// see the user. Public routes such as /api/user/refresh never rotate the
+// refresh token behind the handler's back.
 func (h *AuthnHandler) JWTAuthMW() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if _, ok := h.ignore[c.Request.URL.Path]; ok {
+		access, err := h.token.GetAccessToken(c)
+		if err != nil {
+			c.Set(unauthenticatedKey, "missing access_token in header")
 			c.Next()
 			return
 		}
 
-		access, err := h.token.GetAccessToken(c)
+		claims, err := h.token.ParseToken(c.Request.Context(), access)
 		if err != nil {
-			httputil.InternalError(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "missing access_token in header")))
+			if conf.GetConf().JWT.SilentRefresh && slices.Contains(c.HandlerNames(), requireAuthName) {
+				h.silentRefresh(c)
+			} else {
+				c.Set(unauthenticatedKey, "access_token is invalid or expired, refresh it first")
+			}
+			c.Next()
 			return
 		}
+		ctxcache.Store(c.Request.Context(), consts.SessionDataKeyInCtx, claims.UserID)
+
+		c.Next()
+	}
+}
+
+func (h *AuthnHandler) silentRefresh(c *gin.Context) {
+	refresh, err := c.Cookie(httputil.RefreshCookie)
+	if err != nil {
+		c.Set(unauthenticatedKey, "missing refresh_token in cookie")
+		return
+	}
+	tokens, claims, err := h.token.TryRefresh(c.Request.Context(), refresh, c.Request.UserAgent())
+	if err != nil {
+		c.Set(unauthenticatedKey, "try refresh access_token failed")
+		return
+	}
+	ctxcache.Store(c.Request.Context(), consts.SessionDataKeyInCtx, claims.UserID)
+
+	c.Header("x-access-token", tokens[0])
+	httputil.SetRefreshToken(c, tokens[1])
+}
 
-		if claims, err := h.token.ParseToken(access); err == nil {
-			ctxcache.Store(c.Request.Context(), consts.SessionDataKeyInCtx, claims.UserID)
+// RequireAuth rejects requests JWTAuthMW found no user for.
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := ctxutil.GetUIDFromCtx(c.Request.Context()); ok {
 			c.Next()
 			return
 		}
 
-		refresh, err := c.Cookie("todolist_refresh")
-		if err != nil {
-			httputil.InternalError(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "missing refresh_token in cookie")))
-			return
+		reason, ok := c.Get(unauthenticatedKey)
+		if !ok {
+			reason = "authentication middleware is not installed"
 		}
-		tokens, claims, err := h.token.TryRefresh(refresh, c.Request.UserAgent())
-		if err != nil {
-			httputil.InternalError(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", "try refresh access_token failed")))
-			return
-		}
-		ctxcache.Store(c.Request.Context(), consts.SessionDataKeyInCtx, claims.UserID)
-
-		c.SetSameSite(http.SameSiteLaxMode)
-		c.Header("x-access-token", tokens[0])
-		c.SetCookie("todolist_refresh", tokens[1], int(time.Hour*24), "/", "", false, true)
-
-		c.Next()
+		httputil.Error(c, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", reason.(string))))
 	}
 }
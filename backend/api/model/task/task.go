@@ -6,6 +6,12 @@ type CreateTaskRequest struct {
 	Priority *string `json:"priority,omitempty"`
 }
 
+type GetTaskListRequest struct {
+	Since  *int64 `form:"since"`  // unix milliseconds, only tasks created since then
+	Cursor *int64 `form:"cursor"` // X-Next-Cursor of the previous page
+	Limit  int    `form:"limit" binding:"min=0,max=200"`
+}
+
 type UpdateTaskRequest struct {
 	TaskID      int64   `json:"task_id"`
 	Content     *string `json:"content,omitempty"`
@@ -23,6 +29,7 @@ type Task struct {
 }
 
 type TaskItem struct {
+	ID      int64  `json:"id"`
 	Content string `json:"content"`
 	TaskTyp string `json:"taskTyp"`
 }
@@ -14,6 +14,11 @@ type UpdateAvatarRequest struct {
 	Avatar []byte `json:"avatar,omitempty"`
 }
 
+type UpdateAvatarResponse struct {
+	WebURI  string            `json:"web_uri"`
+	WebURIs map[string]string `json:"web_uris"` // keyed by edge size in px
+}
+
 type UpdateProfileRequest struct {
 	Name           *string `json:"name,omitempty"`
 	UserUniqueName *string `json:"userUniqueName,omitempty"`
@@ -25,11 +30,25 @@ type ResetUserPassword struct {
 }
 
 type User struct {
-	UserID         int64   `json:"userID,string,required"`
-	Name           string  `json:"name"`
-	UserUniqueName string  `json:"user_unique_name"`
-	Email          string  `json:"email"`
-	AvatarURL      string  `json:"avatarURL"`
-	ScreenName     *string `json:"screen_name"`
-	UserCreateTime int64   `json:"userCreateTime"`
+	UserID         int64             `json:"userID,string,required"`
+	Name           string            `json:"name"`
+	UserUniqueName string            `json:"user_unique_name"`
+	Email          string            `json:"email"`
+	AvatarURL      string            `json:"avatarURL"`
+	AvatarURLs     map[string]string `json:"avatarURLs"` // keyed by edge size in px
+	ScreenName     *string           `json:"screen_name"`
+	Role           string            `json:"role"`
+	UserCreateTime int64             `json:"userCreateTime"`
+}
+
+type ListUsersRequest struct {
+	Keyword string `form:"keyword"` // matched against email, unique name and nickname
+	Cursor  *int64 `form:"cursor"`  // X-Next-Cursor of the previous page
+	Limit   int    `form:"limit" binding:"min=0,max=200"`
+}
+
+type AdminUser struct {
+	User
+	Disabled     bool  `json:"disabled"`
+	DisabledTime int64 `json:"disabledTime,omitempty"`
 }
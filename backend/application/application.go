@@ -32,8 +32,8 @@ func Init(ctx context.Context) (*Services, error) {
 }
 
 func initServices(ctx context.Context, infra *appinfra.AppDependencies) (*Services, error) {
-	userSvc := user.InitService(ctx, infra.DB, infra.Storage, infra.IDGenSVC, infra.JWTGen)
-	taskSvc := task.InitService(ctx, infra.DB, infra.IDGenSVC)
+	userSvc := user.InitService(ctx, infra.DB, infra.Cache, infra.Locker, infra.Storage, infra.IDGenSVC, infra.JWTGen)
+	taskSvc := task.InitService(ctx, infra.DB, infra.Cache, infra.IDGenSVC)
 
 	return &Services{
 		Infra:   infra,
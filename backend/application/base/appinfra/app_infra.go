@@ -2,38 +2,90 @@ package appinfra
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/gorm"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	contractidgen "github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/ratelimit"
+	contractstorage "github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/cache/memory"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/cache/redis"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/idgen"
-	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/mysql"
+	memorylock "github.com/crazyfrankie/ddd-todolist/backend/infra/impl/lock/memory"
+	redislock "github.com/crazyfrankie/ddd-todolist/backend/infra/impl/lock/redis"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/migrate"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm"
+	memoryratelimit "github.com/crazyfrankie/ddd-todolist/backend/infra/impl/ratelimit/memory"
+	redisratelimit "github.com/crazyfrankie/ddd-todolist/backend/infra/impl/ratelimit/redis"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/storage/storagemetrics"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/storage/storagetracing"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/storage/urlcache"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/token"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/health"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
 )
 
+// readyTimeout bounds every readiness check, a probe must answer well within
+// the probe timeout of the orchestrator.
+const readyTimeout = 2 * time.Second
+
 type AppDependencies struct {
 	DB       *gorm.DB
-	CacheCli *redis.Client
+	CacheCli *redis.Client // nil when running without Redis
+	Cache    cache.Cache
+	Locker   lock.Locker
+	Limiter  ratelimit.Limiter
 	JWTGen   token.JWT
 	IDGenSVC idgen.IDGenerator
 	Storage  storage.Storage
+	Health   *health.Checker
 }
 
 func Init(ctx context.Context) (*AppDependencies, error) {
 	deps := &AppDependencies{}
 	var err error
 
-	deps.DB, err = mysql.New()
+	deps.DB, err = orm.New(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	deps.CacheCli = redis.New()
+	err = checkSchema(ctx, deps.DB)
+	if err != nil {
+		return nil, err
+	}
 
-	deps.JWTGen = token.New(deps.CacheCli, conf.GetConf().JWT.SignAlgo, conf.GetConf().JWT.SecretKey)
+	deps.CacheCli, deps.Cache, err = newCache()
+	if err != nil {
+		return nil, err
+	}
 
-	deps.IDGenSVC, err = idgen.New(deps.CacheCli)
+	err = registerPoolMetrics(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	if deps.CacheCli != nil {
+		deps.Locker = redislock.New(deps.CacheCli)
+		deps.Limiter = redisratelimit.New(deps.CacheCli)
+	} else {
+		deps.Locker = memorylock.New()
+		deps.Limiter = memoryratelimit.New()
+	}
+
+	deps.JWTGen = token.New(deps.Cache, conf.GetConf().JWT.SignAlgo, conf.GetConf().JWT.SecretKey)
+
+	deps.IDGenSVC, err = newIDGen(ctx, deps)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +94,129 @@ func Init(ctx context.Context) (*AppDependencies, error) {
 	if err != nil {
 		return nil, err
 	}
+	deps.Health = newHealth(deps)
+	deps.Storage = storagetracing.New(storagemetrics.New(deps.Storage))
+	// URLs are memoized in process anyway, only share them when there is Redis
+	var sharedURLs cache.Cache
+	if deps.CacheCli != nil {
+		sharedURLs = deps.Cache
+	}
+	deps.Storage = urlcache.New(deps.Storage, sharedURLs)
 
 	return deps, nil
 }
+
+// Close releases the ID generator lease and the connection pools. It must
+// only be called once nothing uses the dependencies anymore.
+func (d *AppDependencies) Close() error {
+	var errs []error
+	if c, ok := d.IDGenSVC.(io.Closer); ok {
+		errs = append(errs, c.Close())
+	}
+	if d.CacheCli != nil {
+		errs = append(errs, d.CacheCli.Close())
+	}
+	if sqlDB, err := d.DB.DB(); err == nil {
+		errs = append(errs, sqlDB.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// newHealth checks the dependencies a request may need, it must run before
+// the storage is wrapped so that probes are neither cached nor measured.
+func newHealth(deps *AppDependencies) *health.Checker {
+	h := health.New(readyTimeout)
+	h.Add("database", func(ctx context.Context) error {
+		sqlDB, err := deps.DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	if deps.CacheCli != nil {
+		h.Add("redis", func(ctx context.Context) error {
+			return deps.CacheCli.Ping(ctx).Err()
+		})
+	}
+	if p, ok := deps.Storage.(contractstorage.Pinger); ok {
+		h.Add("storage", p.Ping)
+	}
+	if p, ok := deps.IDGenSVC.(contractidgen.Pinger); ok {
+		h.Add("idgen", p.Ping)
+	}
+
+	return h
+}
+
+func registerPoolMetrics(deps *AppDependencies) error {
+	sqlDB, err := deps.DB.DB()
+	if err != nil {
+		return err
+	}
+
+	collectors := []prometheus.Collector{collectors.NewDBStatsCollector(sqlDB, deps.DB.Dialector.Name())}
+	if deps.CacheCli != nil {
+		collectors = append(collectors, redis.PoolCollector(deps.CacheCli))
+	}
+
+	for _, c := range collectors {
+		if err := prometheus.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newCache() (*redis.Client, cache.Cache, error) {
+	switch t := conf.GetConf().Cache.Type; t {
+	case "", "redis":
+		cli, err := redis.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		return cli, redis.NewCache(cli), nil
+	case "memory":
+		return nil, memory.New(), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown cache type: %s", t)
+	}
+}
+
+func newIDGen(ctx context.Context, deps *AppDependencies) (idgen.IDGenerator, error) {
+	c := conf.GetConf().IDGen
+	switch c.Type {
+	case "", "redis":
+		return idgen.New(deps.Cache, c.Namespace, c.ServerID)
+	case "snowflake":
+		return idgen.NewSnowflake(ctx, deps.CacheCli, c.Namespace, c.WorkerID)
+	}
+
+	return nil, fmt.Errorf("unknown id generator type: %s", c.Type)
+}
+
+// checkSchema refuses to start against an out of date schema, unless
+// database.autoMigrate asks to bring it up to date first.
+func checkSchema(ctx context.Context, db *gorm.DB) error {
+	m, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+
+	if conf.GetConf().Database.AutoMigrate {
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			logs.CtxInfof(ctx, "applied migration %04d_%s", mig.Version, mig.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := m.Check(ctx); err != nil {
+		return fmt.Errorf("%w, run `migrate up` first", err)
+	}
+
+	return nil
+}
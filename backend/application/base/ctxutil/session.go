@@ -2,16 +2,29 @@ package ctxutil
 
 import (
 	"context"
+	"errors"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ctxcache"
 	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
 )
 
+// ErrNoSession is what MustGetUIDFromCtx panics with, the recovery middleware
+// reports it as an authentication failure rather than an internal error.
+var ErrNoSession = errors.New("session data is missing in ctx")
+
+// GetUIDFromCtx returns the ID of the user the request is authenticated as,
+// false for anonymous requests.
+func GetUIDFromCtx(ctx context.Context) (int64, bool) {
+	return ctxcache.Get[int64](ctx, consts.SessionDataKeyInCtx)
+}
+
+// MustGetUIDFromCtx is GetUIDFromCtx for code behind routes that require
+// auth, it panics with ErrNoSession for anonymous requests.
 func MustGetUIDFromCtx(ctx context.Context) int64 {
-	data, ok := ctxcache.Get[int64](ctx, consts.SessionDataKeyInCtx)
+	uid, ok := GetUIDFromCtx(ctx)
 	if !ok {
-		panic("mustGetUIDFromCtx: sessionData is nil")
+		panic(ErrNoSession)
 	}
 
-	return data
+	return uid
 }
@@ -7,15 +7,16 @@ import (
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/repository"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
 )
 
-func InitService(ctx context.Context, db *gorm.DB, idgen idgen.IDGenerator) *TaskApplicationService {
+func InitService(ctx context.Context, db *gorm.DB, cacheSvc cache.Cache, idgen idgen.IDGenerator) *TaskApplicationService {
 	task := &TaskApplicationService{}
 
 	task.DomainSVC = service.NewTaskDomain(ctx, &service.Components{
 		IDGen:    idgen,
-		TaskRepo: repository.NewTaskRepository(db),
+		TaskRepo: repository.NewTaskRepository(db, cacheSvc),
 	})
 
 	return task
@@ -2,11 +2,15 @@ package task
 
 import (
 	"context"
+	"time"
 
 	model "github.com/crazyfrankie/ddd-todolist/backend/api/model/task"
 	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/entity"
 	task "github.com/crazyfrankie/ddd-todolist/backend/domain/task/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ptr"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
 )
 
 type TaskApplicationService struct {
@@ -14,6 +18,9 @@ type TaskApplicationService struct {
 }
 
 func (t *TaskApplicationService) AddTask(ctx context.Context, req *model.CreateTaskRequest) (resp *model.Task, err error) {
+	ctx, span := tracing.Start(ctx, "TaskApplicationService.AddTask")
+	defer tracing.End(span, &err)
+
 	userID := ctxutil.MustGetUIDFromCtx(ctx)
 
 	taskInfo, err := t.DomainSVC.CreateTask(ctx, &task.CreateTaskRequest{
@@ -25,11 +32,15 @@ func (t *TaskApplicationService) AddTask(ctx context.Context, req *model.CreateT
 	if err != nil {
 		return nil, err
 	}
+	metrics.TasksCreated.Inc()
 
 	return taskDo2To(taskInfo), nil
 }
 
 func (t *TaskApplicationService) GetTaskDetail(ctx context.Context, taskID int64) (resp *model.Task, err error) {
+	ctx, span := tracing.Start(ctx, "TaskApplicationService.GetTaskDetail")
+	defer tracing.End(span, &err)
+
 	taskInfo, err := t.DomainSVC.GetTaskByID(ctx, taskID)
 	if err != nil {
 		return nil, err
@@ -38,27 +49,49 @@ func (t *TaskApplicationService) GetTaskDetail(ctx context.Context, taskID int64
 	return taskDo2To(taskInfo), nil
 }
 
-func (t *TaskApplicationService) GetTaskList(ctx context.Context) (resp []*model.TaskItem, err error) {
+// GetTaskList returns a page of tasks in creation order. nextCursor is 0 once
+// the last page has been reached.
+func (t *TaskApplicationService) GetTaskList(ctx context.Context, req *model.GetTaskListRequest) (resp []*model.TaskItem, nextCursor int64, err error) {
+	ctx, span := tracing.Start(ctx, "TaskApplicationService.GetTaskList")
+	defer tracing.End(span, &err)
+
 	userID := ctxutil.MustGetUIDFromCtx(ctx)
 
-	tasks, err := t.DomainSVC.GetTaskList(ctx, userID)
+	listReq := &task.GetTaskListRequest{
+		UserID: userID,
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	}
+	if req.Since != nil {
+		listReq.Since = ptr.Of(time.UnixMilli(ptr.From(req.Since)))
+	}
+
+	tasks, err := t.DomainSVC.GetTaskList(ctx, listReq)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	resp = make([]*model.TaskItem, 0, len(tasks))
 	for _, task := range tasks {
 		resp = append(resp, &model.TaskItem{
+			ID:      task.ID,
 			Content: task.Content,
 			TaskTyp: task.TaskTyp.String(),
 		})
 	}
 
-	return resp, nil
+	if req.Limit > 0 && len(tasks) == req.Limit {
+		nextCursor = tasks[len(tasks)-1].ID
+	}
+
+	return resp, nextCursor, nil
 }
 
-func (t *TaskApplicationService) UpdateTask(ctx context.Context, req *model.UpdateTaskRequest) error {
-	err := t.DomainSVC.UpdateTask(ctx, &task.UpdateTaskRequest{
+func (t *TaskApplicationService) UpdateTask(ctx context.Context, req *model.UpdateTaskRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "TaskApplicationService.UpdateTask")
+	defer tracing.End(span, &err)
+
+	completed, err := t.DomainSVC.UpdateTask(ctx, &task.UpdateTaskRequest{
 		TaskID:      req.TaskID,
 		Content:     req.Content,
 		Date:        req.Date,
@@ -68,12 +101,18 @@ func (t *TaskApplicationService) UpdateTask(ctx context.Context, req *model.Upda
 	if err != nil {
 		return err
 	}
+	if completed {
+		metrics.TasksCompleted.Inc()
+	}
 
 	return nil
 }
 
-func (t *TaskApplicationService) DeleteTask(ctx context.Context, taskID int64) error {
-	err := t.DomainSVC.DeleteTask(ctx, taskID)
+func (t *TaskApplicationService) DeleteTask(ctx context.Context, taskID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "TaskApplicationService.DeleteTask")
+	defer tracing.End(span, &err)
+
+	err = t.DomainSVC.DeleteTask(ctx, taskID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/frx/errorx"
+
+	model "github.com/crazyfrankie/ddd-todolist/backend/api/model/user"
+	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
+	user "github.com/crazyfrankie/ddd-todolist/backend/domain/user/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
+)
+
+const defaultUserPageSize = 20
+
+// UserRole resolves the role of uid for route policies, a disabled account
+// holds no role at all. It reads past the user cache, role changes made with
+// the user command cannot evict an in-process cache.
+func (u *UserApplicationService) UserRole(ctx context.Context, uid int64) (role entity.Role, err error) {
+	userInfo, err := u.DomainSVC.GetUserByID(orm.WithPrimary(ctx), uid)
+	if err != nil {
+		return "", err
+	}
+	if userInfo.Disabled() {
+		return "", errorx.New(errno.ErrAccountDisabledCode)
+	}
+
+	return userInfo.Role, nil
+}
+
+// ListUsers returns a page of users in registration order, optionally
+// filtered by keyword. nextCursor is 0 once the last page has been reached.
+func (u *UserApplicationService) ListUsers(ctx context.Context, req *model.ListUsersRequest) (resp []*model.AdminUser, nextCursor int64, err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.ListUsers")
+	defer tracing.End(span, &err)
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultUserPageSize
+	}
+
+	users, err := u.DomainSVC.SearchUsers(ctx, &user.SearchUsersRequest{
+		Keyword: req.Keyword,
+		Cursor:  req.Cursor,
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp = make([]*model.AdminUser, 0, len(users))
+	for _, userDo := range users {
+		resp = append(resp, &model.AdminUser{
+			User:         *userDo2PassportTo(userDo),
+			Disabled:     userDo.Disabled(),
+			DisabledTime: userDo.DisabledAt / 1000,
+		})
+	}
+
+	if len(users) == limit {
+		nextCursor = users[len(users)-1].UserID
+	}
+
+	return resp, nextCursor, nil
+}
+
+// DisableUser disables the account of userID and ends all of its sessions.
+func (u *UserApplicationService) DisableUser(ctx context.Context, userID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.DisableUser")
+	defer tracing.End(span, &err)
+
+	// An admin locking themselves out may leave nobody able to undo it
+	if userID == ctxutil.MustGetUIDFromCtx(ctx) {
+		return errorx.New(errno.ErrDisableSelfCode)
+	}
+
+	err = u.DomainSVC.SetDisabled(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	return u.jwtGen.RevokeAll(ctx, userID)
+}
+
+func (u *UserApplicationService) EnableUser(ctx context.Context, userID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.EnableUser")
+	defer tracing.End(span, &err)
+
+	return u.DomainSVC.SetDisabled(ctx, userID, false)
+}
+
+// ForceLogout ends every session of userID, on every device.
+func (u *UserApplicationService) ForceLogout(ctx context.Context, userID int64) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.ForceLogout")
+	defer tracing.End(span, &err)
+
+	_, err = u.DomainSVC.GetUserProfiles(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return u.jwtGen.RevokeAll(ctx, userID)
+}
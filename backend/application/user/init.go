@@ -7,18 +7,21 @@ import (
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/repository"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/token"
 )
 
-func InitService(ctx context.Context, db *gorm.DB, oss storage.Storage, idgen idgen.IDGenerator, jwtGen token.JWT) *UserApplicationService {
+func InitService(ctx context.Context, db *gorm.DB, cacheSvc cache.Cache, locker lock.Locker, oss storage.Storage, idgen idgen.IDGenerator, jwtGen token.JWT) *UserApplicationService {
 	user := &UserApplicationService{}
 
 	user.DomainSVC = service.NewUserDomain(ctx, &service.Components{
 		IconOSS:  oss,
 		IDGen:    idgen,
-		UserRepo: repository.NewUserRepo(db),
+		Locker:   locker,
+		UserRepo: repository.NewUserRepo(db, cacheSvc),
 	})
 	user.oss = oss
 	user.jwtGen = jwtGen
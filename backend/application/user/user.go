@@ -2,16 +2,23 @@ package user
 
 import (
 	"context"
-	"errors"
 	"net/mail"
+	"strconv"
+
+	"github.com/crazyfrankie/frx/errorx"
 
 	model "github.com/crazyfrankie/ddd-todolist/backend/api/model/user"
 	"github.com/crazyfrankie/ddd-todolist/backend/application/base/ctxutil"
+	uploadEntity "github.com/crazyfrankie/ddd-todolist/backend/domain/upload/entity"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
 	user "github.com/crazyfrankie/ddd-todolist/backend/domain/user/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/token"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ptr"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
 )
 
 type UserApplicationService struct {
@@ -30,9 +37,12 @@ func isValidEmail(email string) bool {
 func (u *UserApplicationService) UserRegister(ctx context.Context, ua string, req *model.EmailRegisterRequest) (
 	resp *model.User, tokens []string, err error,
 ) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.UserRegister")
+	defer tracing.End(span, &err)
+
 	// Verify that the email format is legitimate
 	if !isValidEmail(req.Email) {
-		return nil, nil, errors.New("invalid email")
+		return nil, nil, errorx.New(errno.ErrEmailInvalidCode)
 	}
 
 	userInfo, err := u.DomainSVC.Create(ctx, &user.CreateUserRequest{
@@ -43,7 +53,8 @@ func (u *UserApplicationService) UserRegister(ctx context.Context, ua string, re
 		return nil, nil, err
 	}
 
-	userInfo, err = u.DomainSVC.Login(ctx, req.Email, req.Password)
+	// The user was just written, read it back from the primary
+	userInfo, err = u.DomainSVC.Login(orm.WithPrimary(ctx), req.Email, req.Password)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -59,7 +70,11 @@ func (u *UserApplicationService) UserRegister(ctx context.Context, ua string, re
 func (u *UserApplicationService) UserLogin(ctx context.Context, ua string, req *model.EmailLoginRequest) (
 	resp *model.User, tokens []string, err error,
 ) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.UserLogin")
+	defer tracing.End(span, &err)
+
 	userInfo, err := u.DomainSVC.Login(ctx, req.Email, req.Password)
+	metrics.Logins.WithLabelValues(metrics.Result(err)).Inc()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -73,6 +88,9 @@ func (u *UserApplicationService) UserLogin(ctx context.Context, ua string, req *
 }
 
 func (u *UserApplicationService) UserLogout(ctx context.Context, ua string) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.UserLogout")
+	defer tracing.End(span, &err)
+
 	uid := ctxutil.MustGetUIDFromCtx(ctx)
 
 	err = u.jwtGen.CleanToken(ctx, uid, ua)
@@ -83,33 +101,41 @@ func (u *UserApplicationService) UserLogout(ctx context.Context, ua string) (err
 	return nil
 }
 
-func (u *UserApplicationService) UpdateUserAvatar(ctx context.Context, mimeType string, req *model.UpdateAvatarRequest) (url string, err error) {
-	// Get file suffix by MIME type
-	var ext string
-	switch mimeType {
-	case "image/jpeg", "image/jpg":
-		ext = "jpg"
-	case "image/png":
-		ext = "png"
-	case "image/gif":
-		ext = "gif"
-	case "image/webp":
-		ext = "webp"
-	default:
-		return "", errors.New("unsupported image type")
+// RefreshToken exchanges a refresh token for a new access token, the refresh
+// token is rotated too once two thirds of its lifetime have passed.
+func (u *UserApplicationService) RefreshToken(ctx context.Context, ua string, refresh string) (tokens []string, err error) {
+	_, span := tracing.Start(ctx, "UserApplicationService.RefreshToken")
+	defer tracing.End(span, &err)
+
+	tokens, _, err = u.jwtGen.TryRefresh(ctx, refresh, ua)
+	if err != nil {
+		return nil, errorx.New(errno.ErrAuthFailedCode, errorx.KV("reason", err.Error()))
 	}
 
+	return tokens, nil
+}
+
+func (u *UserApplicationService) UpdateUserAvatar(ctx context.Context, req *model.UpdateAvatarRequest) (resp *model.UpdateAvatarResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.UpdateUserAvatar")
+	defer tracing.End(span, &err)
+
 	uid := ctxutil.MustGetUIDFromCtx(ctx)
 
-	url, err = u.DomainSVC.UpdateAvatar(ctx, uid, ext, req.Avatar)
+	urls, err := u.DomainSVC.UpdateAvatar(ctx, uid, req.Avatar)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return url, nil
+	return &model.UpdateAvatarResponse{
+		WebURI:  urls[uploadEntity.AvatarSizeLarge],
+		WebURIs: avatarURLsDo2To(urls),
+	}, nil
 }
 
 func (u *UserApplicationService) UpdateUserProfile(ctx context.Context, req *model.UpdateProfileRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.UpdateUserProfile")
+	defer tracing.End(span, &err)
+
 	uid := ctxutil.MustGetUIDFromCtx(ctx)
 
 	err = u.DomainSVC.UpdateProfile(ctx, &user.UpdateProfileRequest{
@@ -125,6 +151,9 @@ func (u *UserApplicationService) UpdateUserProfile(ctx context.Context, req *mod
 }
 
 func (u *UserApplicationService) ResetUserPassword(ctx context.Context, req *model.ResetUserPassword) (err error) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.ResetUserPassword")
+	defer tracing.End(span, &err)
+
 	err = u.DomainSVC.ResetPassword(ctx, req.Email, req.Password)
 	if err != nil {
 		return err
@@ -136,6 +165,9 @@ func (u *UserApplicationService) ResetUserPassword(ctx context.Context, req *mod
 func (u *UserApplicationService) GetUserInfo(ctx context.Context) (
 	resp *model.User, err error,
 ) {
+	ctx, span := tracing.Start(ctx, "UserApplicationService.GetUserInfo")
+	defer tracing.End(span, &err)
+
 	userID := ctxutil.MustGetUIDFromCtx(ctx)
 
 	userInfo, err := u.DomainSVC.GetUserInfo(ctx, userID)
@@ -154,6 +186,17 @@ func userDo2PassportTo(userDo *entity.User) *model.User {
 		UserUniqueName: userDo.UniqueName,
 		Email:          userDo.Email,
 		AvatarURL:      userDo.IconURL,
+		AvatarURLs:     avatarURLsDo2To(userDo.IconURLs),
+		Role:           userDo.Role.String(),
 		UserCreateTime: userDo.CreatedAt / 1000,
 	}
 }
+
+func avatarURLsDo2To(urls map[int]string) map[string]string {
+	res := make(map[string]string, len(urls))
+	for size, url := range urls {
+		res[strconv.Itoa(size)] = url
+	}
+
+	return res
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+)
+
+var closers []func(context.Context) error
+
+func onClose(fn func(context.Context) error) {
+	closers = append(closers, fn)
+}
+
+// Close releases what Init acquired, in reverse order, once the server has
+// stopped serving.
+func Close(ctx context.Context) error {
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	closers = nil
+
+	return errors.Join(errs...)
+}
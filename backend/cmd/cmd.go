@@ -2,37 +2,99 @@ package cmd
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/api/handler"
 	"github.com/crazyfrankie/ddd-todolist/backend/api/middleware"
 	"github.com/crazyfrankie/ddd-todolist/backend/application"
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/tracing"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
 )
 
+// untraced are scraped or probed every few seconds, spans of them are noise.
+var untraced = map[string]struct{}{
+	"/healthz": {},
+	"/readyz":  {},
+}
+
+// Admin returns the handler of the admin listener, server.adminAddr. It is
+// kept apart from the API so that metrics are not exposed publicly.
+func Admin() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	return mux
+}
+
 func Init() (*gin.Engine, error) {
+	c, err := conf.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := initLogger(); err != nil {
+		return nil, err
+	}
+	logs.Infof("config loaded: %+v", c.Redacted())
+
+	watchConfig()
+
 	ctx := context.Background()
+	shutdownTracing, err := tracing.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	onClose(shutdownTracing)
+
 	services, err := application.Init(ctx)
 	if err != nil {
 		return nil, err
 	}
+	onClose(func(context.Context) error {
+		return services.Infra.Close()
+	})
+	checker = services.Infra.Health
 
 	userHandler := handler.NewUserHandler(services.UserSvc)
 	taskHandler := handler.NewTaskHandler(services.TaskSvc)
+	adminHandler := handler.NewAdminHandler(services.UserSvc)
+	healthHandler := handler.NewHealthHandler(services.Infra.Health)
 
-	srv := gin.Default()
+	srv := gin.New()
+	// gin trusts X-Forwarded-For from anyone by default
+	if err := srv.SetTrustedProxies(conf.GetConf().Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+	srv.Use(otelgin.Middleware(tracing.ServiceName(), otelgin.WithFilter(func(r *http.Request) bool {
+		_, ok := untraced[r.URL.Path]
+		return !ok
+	})))
 	srv.Use(middleware.CtxCache())
-	srv.Use(middleware.CORS())
 	srv.Use(middleware.SetLogID())
-	srv.Use(middleware.NewAuthnHandler(services.Infra.JWTGen).
-		IgnorePath("/api/user/register").
-		IgnorePath("/api/user/login").
-		JWTAuthMW())
+	srv.Use(middleware.AccessLog())
+	srv.Use(middleware.Metrics())
+	srv.Use(middleware.Recovery())
+	srv.Use(middleware.SecurityHeaders(conf.GetConf().SecurityHeaders))
+	srv.Use(middleware.CORS(conf.GetConf().CORS))
+	// routes requiring a session declare it with middleware.RequireAuth, their
+	// session is resolved and silently refreshed here, before the middlewares
+	// keyed by user
+	srv.Use(middleware.NewAuthnHandler(services.Infra.JWTGen).JWTAuthMW())
+	srv.Use(middleware.RateLimit(services.Infra.Limiter, conf.GetConf().RateLimit))
+	srv.Use(middleware.Idempotency(services.Infra.Cache, services.Infra.Locker))
+
+	healthHandler.RegisterRoute(&srv.RouterGroup)
 
 	apiGroup := srv.Group("api")
 
 	userHandler.RegisterRoute(apiGroup)
 	taskHandler.RegisterRoute(apiGroup)
+	adminHandler.RegisterRoute(apiGroup)
 
 	return srv, nil
 }
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/repository"
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/service"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/cache/redis"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/idgen"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/migrate"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm"
+)
+
+// Exec runs a maintenance sub command instead of starting the server.
+func Exec(args []string) error {
+	switch args[0] {
+	case "id":
+		return inspectIDs(args[1:])
+	case "migrate":
+		return runMigrate(args[1:])
+	case "user":
+		return runUser(args[1:])
+	}
+
+	return fmt.Errorf("unknown command %q, usage: id <id>... | migrate up|down [steps]|status | user role <email> user|admin", args[0])
+}
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+
+	if _, err := conf.Load(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	db, err := orm.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			fmt.Printf("applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		reverted, err := m.Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied at " + time.UnixMilli(s.AppliedAt).Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
+
+// runUser manages users that cannot be managed over the API, such as granting
+// the first admin.
+func runUser(args []string) error {
+	if len(args) != 3 || args[0] != "role" {
+		return fmt.Errorf("usage: user role <email> user|admin")
+	}
+
+	if _, err := conf.Load(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	db, err := orm.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Running servers cache users in Redis, evict the changed one there too.
+	// An in-process cache cannot be reached and expires on its own.
+	var c cache.Cache
+	if t := conf.GetConf().Cache.Type; t == "" || t == "redis" {
+		cli, err := redis.New()
+		if err != nil {
+			return err
+		}
+		defer cli.Close()
+		c = redis.NewCache(cli)
+	}
+
+	svc := service.NewUserDomain(ctx, &service.Components{
+		UserRepo: repository.NewUserRepo(db, c),
+	})
+	u, err := svc.UpdateRole(ctx, args[1], entity.Role(args[2]))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %d (%s) is now %s\n", u.UserID, u.Email, u.Role)
+	return nil
+}
+
+// inspectIDs prints what each ID encodes, it needs no infra at all.
+func inspectIDs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: id <id>...")
+	}
+
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id %q: %w", arg, err)
+		}
+
+		info, err := idgen.Decode(id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("id=%d time=%s unix_ms=%d sequence=%d server_id=%d\n",
+			id, info.Time.UTC().Format(time.RFC3339Nano), info.Time.UnixMilli(), info.Sequence, info.ServerID)
+	}
+
+	return nil
+}
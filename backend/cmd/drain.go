@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/health"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+var checker *health.Checker
+
+// Drain makes /readyz fail and then waits server.drainDelay, so that load
+// balancers stop sending new requests before the server stops accepting
+// them. Call it before shutting the server down.
+func Drain() {
+	if checker == nil {
+		return
+	}
+	checker.Drain()
+
+	if d := conf.GetConf().Server.DrainDelay; d > 0 {
+		logs.Infof("draining, shutting down in %s", d)
+		time.Sleep(d)
+	}
+}
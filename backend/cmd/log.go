@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+func initLogger() error {
+	c := conf.GetConf().Log
+
+	level := logs.LevelInfo
+	if c.Level != "" {
+		var err error
+		level, err = logs.ParseLevel(c.Level)
+		if err != nil {
+			return err
+		}
+	}
+
+	var out io.Writer = os.Stderr
+	if c.File.Path != "" {
+		out = openLogFile(c.File, c.File.Path)
+		if c.File.Stderr {
+			out = io.MultiWriter(os.Stderr, out)
+		}
+	}
+
+	switch c.Format {
+	case "", "json":
+		logs.SetLogger(logs.NewJSONLogger(out, level))
+	case "text":
+		logs.SetOutput(out)
+		logs.SetLevel(level)
+	default:
+		return fmt.Errorf("unknown log format: %s", c.Format)
+	}
+
+	if c.File.ErrorPath != "" {
+		logs.SetErrorOutput(openLogFile(c.File, c.File.ErrorPath))
+	}
+
+	conf.Subscribe(func(prev, cur *conf.Config) {
+		if prev.Log.Level == cur.Log.Level {
+			return
+		}
+		// validated before the reload was applied
+		level, _ := logs.ParseLevel(cur.Log.Level)
+		logs.SetLevel(level)
+		logs.Infof("log level changed from %s to %s", prev.Log.Level, cur.Log.Level)
+	})
+
+	return nil
+}
+
+// openLogFile opens a rotating log file that is closed last on shutdown, as
+// closers run in reverse order.
+func openLogFile(c conf.LogFile, filename string) io.Writer {
+	f := logs.NewRotatingFile(rotateOptions(c, filename))
+	onClose(func(context.Context) error {
+		return f.Close()
+	})
+
+	return f
+}
+
+func rotateOptions(c conf.LogFile, filename string) logs.RotateOptions {
+	return logs.RotateOptions{
+		Filename:   filename,
+		MaxSizeMB:  c.MaxSizeMB,
+		MaxBackups: c.MaxBackups,
+		MaxAgeDays: c.MaxAgeDays,
+		Compress:   c.Compress,
+		Interval:   c.RotateInterval,
+	}
+}
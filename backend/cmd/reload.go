@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+// watchConfig reloads the configuration when its file changes or on SIGHUP.
+func watchConfig() {
+	ctx, cancel := context.WithCancel(context.Background())
+	onClose(func(context.Context) error {
+		cancel()
+		return nil
+	})
+
+	changed, err := conf.Watch(ctx)
+	if err != nil {
+		// a missing config directory is fine, SIGHUP still works
+		logs.Warnf("watch config failed, reload with SIGHUP only, err=%v", err)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+				reloadConfig("config file changed")
+			case <-hup:
+				reloadConfig("SIGHUP")
+			}
+		}
+	}()
+}
+
+func reloadConfig(reason string) {
+	needRestart, err := conf.Reload()
+	if err != nil {
+		logs.Errorf("config reload on %s rejected, keep running config, err=%v", reason, err)
+		return
+	}
+	if len(needRestart) > 0 {
+		logs.Warnf("config reload on %s ignored changes to %v, they need a restart", reason, needRestart)
+	}
+}
@@ -1,33 +1,88 @@
 package conf
 
 import (
-	"os"
-	"path/filepath"
 	"sync"
-
-	"github.com/joho/godotenv"
-	"github.com/kr/pretty"
-	"github.com/spf13/viper"
+	"sync/atomic"
+	"time"
 )
 
 var (
-	conf *Config
-	once sync.Once
+	current atomic.Pointer[Config]
+	loadErr error
+	once    sync.Once
 )
 
 type Config struct {
-	Server Server `yaml:"server"`
-	MySQL  MySQL  `yaml:"mysql"`
-	Redis  Redis  `yaml:"redis"`
-	JWT    JWT    `yaml:"jwt"`
+	Server          Server          `yaml:"server"`
+	Log             Log             `yaml:"log"`
+	Database        Database        `yaml:"database"`
+	MySQL           MySQL           `yaml:"mysql"`
+	Cache           Cache           `yaml:"cache"`
+	Redis           Redis           `yaml:"redis"`
+	JWT             JWT             `yaml:"jwt"`
+	IDGen           IDGen           `yaml:"idGen"`
+	RateLimit       RateLimit       `yaml:"rateLimit"`
+	Tracing         Tracing         `yaml:"tracing"`
+	Storage         Storage         `yaml:"storage"`
+	CORS            CORS            `yaml:"cors"`
+	SecurityHeaders SecurityHeaders `yaml:"securityHeaders"`
+	Cookie          Cookie          `yaml:"cookie"`
 }
 
 type Server struct {
 	Addr string `yaml:"addr"`
+	// AdminAddr serves /metrics apart from the API, keep it off the public
+	// network. Empty disables it.
+	AdminAddr string `yaml:"adminAddr"`
+	// DrainDelay is how long /readyz fails before shutdown starts, give it the
+	// time load balancers need to notice.
+	DrainDelay time.Duration `yaml:"drainDelay"`
+	// TrustedProxies lists the IPs or CIDRs of the reverse proxies whose
+	// X-Forwarded-For is believed, the client IP is the peer address when it
+	// is empty.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+type Log struct {
+	Level  string  `yaml:"level"`  // trace | debug | info | notice | warn | error | fatal, defaults to info
+	Format string  `yaml:"format"` // json | text, defaults to json
+	File   LogFile `yaml:"file"`
+}
+
+type LogFile struct {
+	Path           string        `yaml:"path"`      // empty logs to stderr only
+	ErrorPath      string        `yaml:"errorPath"` // warnings and above are also written here, empty disables
+	Stderr         bool          `yaml:"stderr"`    // keep logging to stderr as well as to path
+	MaxSizeMB      int           `yaml:"maxSizeMB"`
+	MaxBackups     int           `yaml:"maxBackups"`
+	MaxAgeDays     int           `yaml:"maxAgeDays"`
+	Compress       bool          `yaml:"compress"`
+	RotateInterval time.Duration `yaml:"rotateInterval"` // also rotate at every multiple of it since local midnight, 0 rotates by size only
+}
+
+type Database struct {
+	Driver      string `yaml:"driver"`      // mysql | sqlite, defaults to mysql
+	AutoMigrate bool   `yaml:"autoMigrate"` // apply pending migrations at startup instead of refusing to start
+	SQLite      SQLite `yaml:"sqlite"`
+}
+
+type SQLite struct {
+	Path string `yaml:"path"` // database file, ":memory:" for a throwaway in-process database
 }
 
 type MySQL struct {
-	DSN string `yaml:"dsn"`
+	DSN             string        `yaml:"dsn"`
+	Replicas        []string      `yaml:"replicas"` // read replica DSNs
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+	SlowThreshold   time.Duration `yaml:"slowThreshold"` // statements slower than this are logged as warnings
+	ConnectRetries  int           `yaml:"connectRetries"`
+}
+
+type Cache struct {
+	Type string `yaml:"type"` // redis | memory, defaults to redis. memory only suits a single instance
 }
 
 type Redis struct {
@@ -38,43 +93,94 @@ type Redis struct {
 type JWT struct {
 	SignAlgo  string `yaml:"signAlgo"`
 	SecretKey string `yaml:"secretKey"`
+	// SilentRefresh lets requests to routes requiring a session refresh an
+	// expired access token from the refresh cookie. It skips the CSRF check of
+	// /api/user/refresh, keep it off unless old clients depend on it.
+	SilentRefresh bool `yaml:"silentRefresh"`
 }
 
-func GetConf() *Config {
-	once.Do(initConf)
-	return conf
+type IDGen struct {
+	Type      string `yaml:"type"` // redis | snowflake, defaults to redis, whose counters live in the configured cache
+	Namespace string `yaml:"namespace"`
+	ServerID  int64  `yaml:"serverID"` // server id of the redis generator
+	WorkerID  int64  `yaml:"workerID"` // snowflake worker id, 0 leases one from redis
 }
 
-func initConf() {
-	env := getEnv()
-	prefix := "conf"
+type RateLimit struct {
+	Enabled  bool         `yaml:"enabled"`
+	Policies []RatePolicy `yaml:"policies"` // the longest matching prefix applies
+}
 
-	envFile := filepath.Join(prefix, filepath.Join(env, ".env"))
-	err := godotenv.Load(envFile)
-	if err != nil {
-		panic(err)
-	}
+type RatePolicy struct {
+	Prefix string        `yaml:"prefix"` // request path prefix, matched on whole segments
+	By     string        `yaml:"by"`     // user | ip, user falls back to ip for anonymous requests
+	Rate   int           `yaml:"rate"`   // requests per period
+	Period time.Duration `yaml:"period"`
+	Burst  int           `yaml:"burst"` // defaults to rate
+}
 
-	confPath := filepath.Join(prefix, filepath.Join(env, "conf.yml"))
-	viper.SetConfigFile(confPath)
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"` // none | stdout | otlp, defaults to none
+	Endpoint    string  `yaml:"endpoint"` // otlp http endpoint, host:port
+	Insecure    bool    `yaml:"insecure"` // plain http to the otlp endpoint
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"` // share of new traces recorded, 0 to 1, defaults to 1
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		panic(err)
-	}
+type Storage struct {
+	Type   string `yaml:"type"` // minio
+	Bucket string `yaml:"bucket"`
+	MinIO  MinIO  `yaml:"minio"`
+}
 
-	conf = new(Config)
-	if err := viper.Unmarshal(&conf); err != nil {
-		panic(err)
-	}
+type MinIO struct {
+	Endpoint  string `yaml:"endpoint"` // host:port
+	AccessKey string `yaml:"accessKey"`
+	SecretKey string `yaml:"secretKey"`
+	UseSSL    bool   `yaml:"useSSL"`
+}
+
+type CORS struct {
+	AllowOrigins []string      `yaml:"allowOrigins"` // scheme://host[:port], credentials are allowed so * is not
+	AllowMethods []string      `yaml:"allowMethods"`
+	AllowHeaders []string      `yaml:"allowHeaders"`
+	MaxAge       time.Duration `yaml:"maxAge"` // how long browsers may cache a preflight response
+}
 
-	pretty.Printf("%#v\n", conf)
+type SecurityHeaders struct {
+	HSTSMaxAge            time.Duration `yaml:"hstsMaxAge"` // 0 leaves Strict-Transport-Security out
+	HSTSIncludeSubdomains bool          `yaml:"hstsIncludeSubdomains"`
+	FrameOptions          string        `yaml:"frameOptions"` // DENY | SAMEORIGIN
+	ReferrerPolicy        string        `yaml:"referrerPolicy"`
+	ContentSecurityPolicy string        `yaml:"contentSecurityPolicy"` // empty leaves the header out
 }
 
-func getEnv() string {
-	env := os.Getenv("GoEnv")
-	if env == "" {
-		return "test"
+type Cookie struct {
+	Domain   string `yaml:"domain"` // empty scopes the cookie to the exact host
+	Secure   bool   `yaml:"secure"`
+	SameSite string `yaml:"sameSite"` // lax | strict | none, defaults to lax. none requires secure
+}
+
+// Load reads the configuration on first use, later calls return the same
+// result. Validation errors are reported all at once.
+func Load() (*Config, error) {
+	once.Do(func() {
+		var c *Config
+		c, loadErr = load()
+		current.Store(c)
+	})
+
+	return current.Load(), loadErr
+}
+
+// GetConf returns the configuration and panics if it can not be loaded, call
+// Load first where the error can be reported. The returned value must not be
+// modified, a reload replaces it rather than changing it in place.
+func GetConf() *Config {
+	c, err := Load()
+	if err != nil {
+		panic(err)
 	}
 
-	return env
+	return c
 }
@@ -0,0 +1,196 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/viper"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
+)
+
+// EnvPrefix prefixes the environment variables that override config keys,
+// e.g. TODOLIST_MYSQL_DSN overrides mysql.dsn.
+const EnvPrefix = "TODOLIST"
+
+// load layers, from lowest to highest priority: defaults, conf/<env>/conf.yml
+// and the environment, which conf/<env>/.env adds to without overriding it.
+// Both files are optional.
+func load() (*Config, error) {
+	dir := filepath.Dir(configFile())
+
+	if err := loadDotenv(filepath.Join(dir, ".env")); err != nil {
+		return nil, fmt.Errorf("load .env failed, err: %w", err)
+	}
+
+	v := viper.New()
+	setDefaults(v)
+
+	v.SetConfigFile(configFile())
+	if err := v.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read config failed, err: %w", err)
+	}
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	if err := bindEnv(v, reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, err
+	}
+	if err := bindLegacyEnv(v); err != nil {
+		return nil, err
+	}
+
+	c := new(Config)
+	if err := v.Unmarshal(c); err != nil {
+		return nil, fmt.Errorf("decode config failed, err: %w", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return c, nil
+}
+
+var (
+	dotenvMu sync.Mutex
+	// dotenvKeys are the variables the environment got from .env rather than
+	// from the process environment.
+	dotenvKeys = map[string]bool{}
+)
+
+// loadDotenv sets the variables of the .env file at path that the process
+// environment does not set itself. Unlike godotenv.Load it can run again: the
+// variables it set earlier take their new values, or are unset when they were
+// removed from the file, so that a reload sees the edits.
+func loadDotenv(path string) error {
+	env, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		env = map[string]string{}
+	} else if err != nil {
+		return err
+	}
+
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	for k := range dotenvKeys {
+		if _, ok := env[k]; !ok {
+			_ = os.Unsetenv(k)
+			delete(dotenvKeys, k)
+		}
+	}
+	for k, val := range env {
+		if _, set := os.LookupEnv(k); set && !dotenvKeys[k] {
+			continue
+		}
+		if err := os.Setenv(k, val); err != nil {
+			return err
+		}
+		dotenvKeys[k] = true
+	}
+
+	return nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.addr", ":8080")
+	v.SetDefault("server.adminAddr", "127.0.0.1:9091")
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "json")
+	v.SetDefault("database.driver", "mysql")
+	v.SetDefault("cache.type", "redis")
+	v.SetDefault("idGen.type", "redis")
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.serviceName", "todolist")
+	v.SetDefault("tracing.sampleRatio", 1)
+	v.SetDefault("storage.type", "minio")
+	v.SetDefault("cors.allowOrigins", []string{"http://localhost:3001"})
+	v.SetDefault("cors.allowMethods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	v.SetDefault("cors.allowHeaders", []string{"Origin", "Content-Type", "Authorization", "Idempotency-Key", "X-Log-Id", "X-CSRF-Token"})
+	v.SetDefault("cors.maxAge", 12*time.Hour)
+	v.SetDefault("securityHeaders.hstsMaxAge", 365*24*time.Hour)
+	v.SetDefault("securityHeaders.frameOptions", "DENY")
+	v.SetDefault("securityHeaders.referrerPolicy", "no-referrer")
+	v.SetDefault("securityHeaders.contentSecurityPolicy", "default-src 'none'; frame-ancestors 'none'")
+	v.SetDefault("cookie.sameSite", "lax")
+}
+
+// bindEnv makes every scalar key of t overridable from the environment, viper
+// only looks up the keys it already knows of when unmarshalling. Lists of
+// structs, like rate limit policies, can only be set in the file.
+func bindEnv(v *viper.Viper, t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := prefix + f.Tag.Get("yaml")
+
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			if err := bindEnv(v, f.Type, key+"."); err != nil {
+				return err
+			}
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct:
+		default:
+			if err := v.BindEnv(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// bindLegacyEnv keeps the storage variables that predate the storage section
+// working, the prefixed names take precedence.
+func bindLegacyEnv(v *viper.Viper) error {
+	legacy := map[string]string{
+		"storage.type":            consts.StorageType,
+		"storage.bucket":          consts.StorageBucket,
+		"storage.minio.endpoint":  consts.MinIOEndpoint,
+		"storage.minio.accessKey": consts.MinIOAK,
+		"storage.minio.secretKey": consts.MinIOSK,
+	}
+	for key, name := range legacy {
+		if err := v.BindEnv(key, envName(key), name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// getEnv names the config directory to read, TODOLIST_ENV wins over the older
+// GoEnv.
+func getEnv() string {
+	for _, name := range []string{EnvPrefix + "_ENV", "GoEnv"} {
+		if env := os.Getenv(name); env != "" {
+			return env
+		}
+	}
+
+	return "test"
+}
+
+func configFile() string {
+	return filepath.Join(confDir(), getEnv(), "conf.yml")
+}
+
+func confDir() string {
+	if dir := os.Getenv(EnvPrefix + "_CONF_DIR"); dir != "" {
+		return dir
+	}
+
+	return "conf"
+}
@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"slices"
+	"strings"
+)
+
+const redacted = "******"
+
+// Redacted returns a copy of c that is safe to log, secrets and the passwords
+// inside DSNs are masked.
+func (c *Config) Redacted() Config {
+	r := *c
+
+	r.MySQL.DSN = RedactDSN(r.MySQL.DSN)
+	r.MySQL.Replicas = slices.Clone(r.MySQL.Replicas)
+	for i, dsn := range r.MySQL.Replicas {
+		r.MySQL.Replicas[i] = RedactDSN(dsn)
+	}
+	r.Redis.Password = redact(r.Redis.Password)
+	r.JWT.SecretKey = redact(r.JWT.SecretKey)
+	r.Storage.MinIO.SecretKey = redact(r.Storage.MinIO.SecretKey)
+
+	return r
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// RedactDSN masks the password of a user:password@protocol(address)/db DSN,
+// for DSNs that end up in logs or errors.
+func RedactDSN(dsn string) string {
+	at := strings.LastIndex(dsn, "@")
+	if at < 0 {
+		return dsn
+	}
+	colon := strings.Index(dsn[:at], ":")
+	if colon < 0 {
+		return dsn
+	}
+
+	return dsn[:colon+1] + redacted + dsn[at:]
+}
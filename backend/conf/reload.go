@@ -0,0 +1,128 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var (
+	reloadMu    sync.Mutex
+	subscribers []func(prev, cur *Config)
+)
+
+// Subscribe registers fn to be called after every applied reload, with the
+// configuration before and after it. fn runs on the reloading goroutine and
+// should only compare the settings it uses and swap them in.
+func Subscribe(fn func(prev, cur *Config)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	subscribers = append(subscribers, fn)
+}
+
+// Reload reads the configuration again and applies the part of it that may
+// change at runtime: log.level, rateLimit and cors. The new configuration must be
+// valid as a whole, otherwise it is rejected and the running one is kept.
+//
+// Sections changed in ways that can not be applied are returned, they only
+// take effect after a restart.
+func Reload() (needRestart []string, err error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	prev := current.Load()
+	if prev == nil {
+		return nil, errors.New("config is not loaded")
+	}
+
+	next, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	cur := *prev
+	cur.Log.Level = next.Log.Level
+	cur.RateLimit = next.RateLimit
+	cur.CORS = next.CORS
+
+	needRestart = changedSections(&cur, next)
+	if reflect.DeepEqual(prev, &cur) {
+		return needRestart, nil
+	}
+
+	current.Store(&cur)
+	for _, fn := range subscribers {
+		fn(prev, &cur)
+	}
+
+	return needRestart, nil
+}
+
+// changedSections lists the top level keys whose values differ.
+func changedSections(a, b *Config) []string {
+	var changed []string
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			changed = append(changed, va.Type().Field(i).Tag.Get("yaml"))
+		}
+	}
+
+	return changed
+}
+
+// Watch sends on the returned channel whenever the config directory changes,
+// until ctx is done. Bursts of changes are coalesced. The directory is watched
+// rather than the file, editors and mounted config maps replace the file
+// instead of writing to it.
+func Watch(ctx context.Context) (<-chan struct{}, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Add(filepath.Dir(configFile())); err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	const settle = 200 * time.Millisecond
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer w.Close()
+
+		timer := time.NewTimer(settle)
+		timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if ev.Op != fsnotify.Chmod {
+					timer.Reset(settle)
+				}
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+			case <-timer.C:
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
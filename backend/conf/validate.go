@@ -0,0 +1,137 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Validate checks the whole configuration and joins every problem found, so
+// that a bad deployment is fixed in one round trip.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.require("server.addr", c.Server.Addr)
+	if c.Server.AdminAddr != "" && c.Server.AdminAddr == c.Server.Addr {
+		v.fail("server.adminAddr", "must differ from server.addr")
+	}
+	v.nonNegative("server.drainDelay", int64(c.Server.DrainDelay))
+	for i, proxy := range c.Server.TrustedProxies {
+		if !isIPOrCIDR(proxy) {
+			v.fail(fmt.Sprintf("server.trustedProxies[%d]", i), fmt.Sprintf("%q is not an IP or a CIDR", proxy))
+		}
+	}
+
+	v.oneOf("log.level", strings.ToLower(c.Log.Level), "trace", "debug", "info", "notice", "warn", "error", "fatal")
+	v.oneOf("log.format", c.Log.Format, "json", "text")
+	v.nonNegative("log.file.rotateInterval", int64(c.Log.File.RotateInterval))
+
+	v.oneOf("database.driver", c.Database.Driver, "mysql", "sqlite")
+	switch c.Database.Driver {
+	case "mysql":
+		v.require("mysql.dsn", c.MySQL.DSN)
+	case "sqlite":
+		v.require("database.sqlite.path", c.Database.SQLite.Path)
+	}
+
+	v.oneOf("cache.type", c.Cache.Type, "redis", "memory")
+	v.oneOf("idGen.type", c.IDGen.Type, "redis", "snowflake")
+	if c.Cache.Type == "redis" || c.IDGen.Type == "snowflake" {
+		v.require("redis.addr", c.Redis.Addr)
+	}
+	if c.IDGen.Type == "snowflake" && c.IDGen.WorkerID == 0 && c.Cache.Type != "redis" {
+		v.fail("idGen.workerID", "must be set when cache.type is not redis, leasing one needs redis")
+	}
+
+	v.oneOf("jwt.signAlgo", c.JWT.SignAlgo, "HS256", "HS384", "HS512")
+	v.require("jwt.secretKey", c.JWT.SecretKey)
+
+	for i, p := range c.RateLimit.Policies {
+		key := fmt.Sprintf("rateLimit.policies[%d]", i)
+		v.require(key+".prefix", p.Prefix)
+		v.oneOf(key+".by", p.By, "user", "ip")
+		v.positive(key+".rate", int64(p.Rate))
+		v.positive(key+".period", int64(p.Period))
+		v.nonNegative(key+".burst", int64(p.Burst))
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "otlp")
+	if c.Tracing.Exporter == "otlp" {
+		v.require("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail("tracing.sampleRatio", "must be between 0 and 1")
+	}
+
+	v.oneOf("storage.type", c.Storage.Type, "minio")
+	v.require("storage.bucket", c.Storage.Bucket)
+	if c.Storage.Type == "minio" {
+		v.require("storage.minio.endpoint", c.Storage.MinIO.Endpoint)
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		// the CORS middleware cannot be built without any
+		v.fail("cors.allowOrigins", "needs at least one origin")
+	}
+	for i, origin := range c.CORS.AllowOrigins {
+		key := fmt.Sprintf("cors.allowOrigins[%d]", i)
+		if origin == "*" {
+			v.fail(key, "must not be *, cookies are sent cross origin")
+		} else if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			v.fail(key, fmt.Sprintf("%q must start with http:// or https://", origin))
+		}
+	}
+	v.nonNegative("cors.maxAge", int64(c.CORS.MaxAge))
+
+	v.nonNegative("securityHeaders.hstsMaxAge", int64(c.SecurityHeaders.HSTSMaxAge))
+	v.oneOf("securityHeaders.frameOptions", strings.ToUpper(c.SecurityHeaders.FrameOptions), "DENY", "SAMEORIGIN")
+
+	v.oneOf("cookie.sameSite", strings.ToLower(c.Cookie.SameSite), "lax", "strict", "none")
+	if strings.EqualFold(c.Cookie.SameSite, "none") && !c.Cookie.Secure {
+		v.fail("cookie.secure", "must be true when cookie.sameSite is none, browsers drop the cookie otherwise")
+	}
+
+	return errors.Join(v.errs...)
+}
+
+func isIPOrCIDR(s string) bool {
+	if _, err := netip.ParsePrefix(s); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(key, msg string) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", key, msg))
+}
+
+func (v *validator) require(key, val string) {
+	if val == "" {
+		v.fail(key, "is required")
+	}
+}
+
+func (v *validator) oneOf(key, val string, allowed ...string) {
+	if !slices.Contains(allowed, val) {
+		v.fail(key, fmt.Sprintf("%q is not one of %s", val, strings.Join(allowed, " | ")))
+	}
+}
+
+func (v *validator) positive(key string, val int64) {
+	if val <= 0 {
+		v.fail(key, "must be positive")
+	}
+}
+
+func (v *validator) nonNegative(key string, val int64) {
+	if val < 0 {
+		v.fail(key, "must not be negative")
+	}
+}
@@ -13,6 +13,30 @@ func (t TaskStatus) String() string {
 	return string(t)
 }
 
+// TaskPriority is stored as plain text so it is portable across databases,
+// the allowed values are enforced here rather than by a MySQL ENUM.
+type TaskPriority string
+
+const (
+	PriorityImportantUrgent       TaskPriority = "important and urgent"
+	PriorityImportantNotUrgent    TaskPriority = "important but not urgent"
+	PriorityNotImportantUrgent    TaskPriority = "not important but urgent"
+	PriorityNotImportantNotUrgent TaskPriority = "neither important or urgent"
+	DefaultPriority                            = PriorityNotImportantNotUrgent
+)
+
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case PriorityImportantUrgent, PriorityImportantNotUrgent, PriorityNotImportantUrgent, PriorityNotImportantNotUrgent:
+		return true
+	}
+	return false
+}
+
+func (p TaskPriority) String() string {
+	return string(p)
+}
+
 type Task struct {
 	ID int64
 
@@ -4,10 +4,12 @@ import (
 	"context"
 	"time"
 
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/model"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/query"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
 )
 
 func NewTaskDAO(db *gorm.DB) *TaskDAO {
@@ -20,12 +22,37 @@ type TaskDAO struct {
 	query *query.Query
 }
 
+// readQuery routes reads to a replica unless ctx asks to read its own writes.
+func (t *TaskDAO) readQuery(ctx context.Context) *query.Query {
+	if orm.UsePrimary(ctx) {
+		return t.query.WriteDB()
+	}
+	return t.query
+}
+
 func (t *TaskDAO) CreateTask(ctx context.Context, task *model.Task) error {
 	return t.query.WithContext(ctx).Task.Create(task)
 }
 
-func (t *TaskDAO) GetTaskList(ctx context.Context, userID int64) ([]*model.Task, error) {
-	tasks, err := t.query.WithContext(ctx).Task.Where(t.query.Task.UserID.Eq(userID)).Find()
+// TaskListFilter selects a user's tasks. IDs are time ordered, so MinID doubles
+// as both a "created since" bound and a pagination cursor.
+type TaskListFilter struct {
+	UserID int64
+	MinID  int64 // inclusive lower bound, 0 means none
+	Limit  int   // 0 means no limit
+}
+
+func (t *TaskDAO) GetTaskList(ctx context.Context, filter *TaskListFilter) ([]*model.Task, error) {
+	do := t.readQuery(ctx).WithContext(ctx).Task.Where(t.query.Task.UserID.Eq(filter.UserID))
+	if filter.MinID > 0 {
+		do = do.Where(t.query.Task.ID.Gte(filter.MinID))
+	}
+	do = do.Order(t.query.Task.ID)
+	if filter.Limit > 0 {
+		do = do.Limit(filter.Limit)
+	}
+
+	tasks, err := do.Find()
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +61,7 @@ func (t *TaskDAO) GetTaskList(ctx context.Context, userID int64) ([]*model.Task,
 }
 
 func (t *TaskDAO) GetTaskByID(ctx context.Context, taskID int64) (*model.Task, error) {
-	task, err := t.query.WithContext(ctx).Task.Where(t.query.Task.ID.Eq(taskID)).First()
+	task, err := t.readQuery(ctx).WithContext(ctx).Task.Where(t.query.Task.ID.Eq(taskID)).First()
 	if err != nil {
 		return nil, err
 	}
@@ -51,6 +78,42 @@ func (t *TaskDAO) UpdateTask(ctx context.Context, taskID int64, updates map[stri
 	return err
 }
 
+// CompleteTask marks the task completed and applies updates along with it,
+// completed is false when it already was.
+func (t *TaskDAO) CompleteTask(ctx context.Context, taskID int64, updates map[string]any) (completed bool, err error) {
+	if updates == nil {
+		updates = make(map[string]any)
+	}
+	updates["is_completed"] = true
+	if _, ok := updates["updated_at"]; !ok {
+		updates["updated_at"] = time.Now().UnixMilli()
+	}
+
+	err = t.query.Transaction(func(tx *query.Query) error {
+		task := tx.Task
+		// is_completed is nullable
+		res, err := tx.WithContext(ctx).Task.Where(
+			task.ID.Eq(taskID), field.Or(task.IsCompleted.Is(false), task.IsCompleted.IsNull()),
+		).Updates(updates)
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected > 0 {
+			completed = true
+			return nil
+		}
+
+		// already completed, the other fields are still updated
+		_, err = tx.WithContext(ctx).Task.Where(task.ID.Eq(taskID)).Updates(updates)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return completed, nil
+}
+
 func (t *TaskDAO) DeleteTask(ctx context.Context, taskID int64) error {
 	_, err := t.query.WithContext(ctx).Task.Where(t.query.Task.ID.Eq(taskID)).Delete()
 	if err != nil {
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/cacheaside"
+)
+
+const (
+	taskCacheTTL     = 10 * time.Minute
+	taskMissCacheTTL = time.Minute
+)
+
+// cachedTaskRepo caches tasks by ID in front of the database. Task lists are
+// paged and filtered, so they are always read from the database.
+type cachedTaskRepo struct {
+	TaskRepository
+	cache *cacheaside.Cache[model.Task]
+}
+
+func newCachedTaskRepo(repo TaskRepository, c cache.Cache) TaskRepository {
+	return &cachedTaskRepo{
+		TaskRepository: repo,
+		cache:          cacheaside.New[model.Task](c, taskCacheTTL, taskMissCacheTTL, gorm.ErrRecordNotFound),
+	}
+}
+
+func (r *cachedTaskRepo) GetTaskByID(ctx context.Context, taskID int64) (*model.Task, error) {
+	if orm.UsePrimary(ctx) {
+		return r.TaskRepository.GetTaskByID(ctx, taskID)
+	}
+
+	return r.cache.Get(ctx, taskCacheKey(taskID), func(ctx context.Context) (*model.Task, error) {
+		return r.TaskRepository.GetTaskByID(ctx, taskID)
+	})
+}
+
+func (r *cachedTaskRepo) CreateTask(ctx context.Context, task *model.Task) error {
+	if err := r.TaskRepository.CreateTask(ctx, task); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, taskCacheKey(task.ID))
+	return nil
+}
+
+func (r *cachedTaskRepo) UpdateTask(ctx context.Context, taskID int64, updates map[string]any) error {
+	if err := r.TaskRepository.UpdateTask(ctx, taskID, updates); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, taskCacheKey(taskID))
+	return nil
+}
+
+func (r *cachedTaskRepo) CompleteTask(ctx context.Context, taskID int64, updates map[string]any) (bool, error) {
+	completed, err := r.TaskRepository.CompleteTask(ctx, taskID, updates)
+	if err != nil {
+		return false, err
+	}
+
+	r.cache.Del(ctx, taskCacheKey(taskID))
+	return completed, nil
+}
+
+func (r *cachedTaskRepo) DeleteTask(ctx context.Context, taskID int64) error {
+	if err := r.TaskRepository.DeleteTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, taskCacheKey(taskID))
+	return nil
+}
+
+func taskCacheKey(taskID int64) string {
+	return fmt.Sprintf("task:info:%d", taskID)
+}
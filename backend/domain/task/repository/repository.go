@@ -7,16 +7,26 @@ import (
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 )
 
+type TaskListFilter = dal.TaskListFilter
+
 type TaskRepository interface {
 	CreateTask(ctx context.Context, task *model.Task) error
-	GetTaskList(ctx context.Context, userID int64) ([]*model.Task, error)
+	GetTaskList(ctx context.Context, filter *TaskListFilter) ([]*model.Task, error)
 	GetTaskByID(ctx context.Context, taskID int64) (*model.Task, error)
 	UpdateTask(ctx context.Context, taskID int64, updates map[string]any) error
+	CompleteTask(ctx context.Context, taskID int64, updates map[string]any) (completed bool, err error)
 	DeleteTask(ctx context.Context, taskID int64) error
 }
 
-func NewTaskRepository(db *gorm.DB) TaskRepository {
-	return dal.NewTaskDAO(db)
+// NewTaskRepository returns the task repository, cached in c when it is not nil.
+func NewTaskRepository(db *gorm.DB, c cache.Cache) TaskRepository {
+	repo := TaskRepository(dal.NewTaskDAO(db))
+	if c != nil {
+		repo = newCachedTaskRepo(repo, c)
+	}
+
+	return repo
 }
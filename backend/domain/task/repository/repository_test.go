@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/migrate"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/sqlite"
+)
+
+// newTestRepo returns the uncached repository on a migrated in-memory database.
+func newTestRepo(t *testing.T) TaskRepository {
+	t.Helper()
+
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	m, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewTaskRepository(db, nil)
+}
+
+func TestTaskRepoCRUD(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for id := int64(1); id <= 3; id++ {
+		err := repo.CreateTask(ctx, &model.Task{ID: id, UserID: 1 + id/3, Content: "task", Priority: "important and urgent"})
+		if err != nil {
+			t.Fatalf("create task %d: %v", id, err)
+		}
+	}
+
+	tasks, err := repo.GetTaskList(ctx, &TaskListFilter{UserID: 1, MinID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != 2 {
+		t.Errorf("GetTaskList = %v, want only task 2", tasks)
+	}
+
+	if err := repo.UpdateTask(ctx, 1, map[string]any{"content": "updated"}); err != nil {
+		t.Fatal(err)
+	}
+	task, err := repo.GetTaskByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Content != "updated" || task.UpdatedAt == 0 {
+		t.Errorf("task after update = %+v, want content updated and updated_at set", task)
+	}
+
+	if err := repo.DeleteTask(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetTaskByID(ctx, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("get deleted task: err = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+}
+
+// TestTaskRepoPriority checks that the TEXT column standing in for the MySQL
+// ENUM keeps its default and its set of values.
+func TestTaskRepoPriority(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	if err := repo.CreateTask(ctx, &model.Task{ID: 1, UserID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	task, err := repo.GetTaskByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Priority != "neither important or urgent" {
+		t.Errorf("default priority = %q, want %q", task.Priority, "neither important or urgent")
+	}
+
+	if err := repo.UpdateTask(ctx, 1, map[string]any{"priority": "not important but urgent"}); err != nil {
+		t.Fatal(err)
+	}
+	if task, err = repo.GetTaskByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if task.Priority != "not important but urgent" {
+		t.Errorf("priority after update = %q, want %q", task.Priority, "not important but urgent")
+	}
+
+	if err := repo.CreateTask(ctx, &model.Task{ID: 2, UserID: 1, Priority: "urgent"}); err == nil {
+		t.Error("created a task with a priority outside the enum")
+	}
+	if err := repo.UpdateTask(ctx, 1, map[string]any{"priority": "urgent"}); err == nil {
+		t.Error("updated a task to a priority outside the enum")
+	}
+}
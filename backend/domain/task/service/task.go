@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/entity"
 )
@@ -21,10 +22,18 @@ type UpdateTaskRequest struct {
 	IsCompleted *bool
 }
 
+type GetTaskListRequest struct {
+	UserID int64
+	Since  *time.Time // only tasks created at or after Since
+	Cursor *int64     // ID of the last task of the previous page
+	Limit  int        // page size, 0 means all
+}
+
 type Task interface {
 	CreateTask(ctx context.Context, req *CreateTaskRequest) (*entity.Task, error)
-	GetTaskList(ctx context.Context, userID int64) ([]*entity.Task, error)
+	GetTaskList(ctx context.Context, req *GetTaskListRequest) ([]*entity.Task, error)
 	GetTaskByID(ctx context.Context, taskID int64) (*entity.Task, error)
-	UpdateTask(ctx context.Context, req *UpdateTaskRequest) error
+	// UpdateTask reports whether it completed a task that was not completed yet.
+	UpdateTask(ctx context.Context, req *UpdateTaskRequest) (completed bool, err error)
 	DeleteTask(ctx context.Context, taskID int64) error
 }
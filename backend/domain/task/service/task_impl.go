@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/crazyfrankie/frx/errorx"
+
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/entity"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/internal/dal/model"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/task/repository"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ptr"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
 )
 
 type Components struct {
@@ -44,7 +48,11 @@ func (t *taskImpl) CreateTask(ctx context.Context, req *CreateTaskRequest) (task
 	if req.Date != nil {
 		newTask.DueTime = ptr.From(req.Date)
 	}
+	newTask.Priority = entity.DefaultPriority.String()
 	if req.Priority != nil {
+		if !entity.TaskPriority(ptr.From(req.Priority)).IsValid() {
+			return nil, errorx.New(errno.ErrTaskPriorityInvalidCode)
+		}
 		newTask.Priority = ptr.From(req.Priority)
 	}
 
@@ -53,11 +61,29 @@ func (t *taskImpl) CreateTask(ctx context.Context, req *CreateTaskRequest) (task
 		return nil, err
 	}
 
-	return taskPo2Do(newTask), nil
+	// Read back from the primary, a replica may not have the row yet and the
+	// database fills in defaults such as priority.
+	created, err := t.TaskRepo.GetTaskByID(orm.WithPrimary(ctx), taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return taskPo2Do(created), nil
 }
 
-func (t *taskImpl) GetTaskList(ctx context.Context, userID int64) (tasks []*entity.Task, err error) {
-	taskModels, err := t.TaskRepo.GetTaskList(ctx, userID)
+func (t *taskImpl) GetTaskList(ctx context.Context, req *GetTaskListRequest) (tasks []*entity.Task, err error) {
+	filter := &repository.TaskListFilter{
+		UserID: req.UserID,
+		Limit:  req.Limit,
+	}
+	if req.Since != nil {
+		filter.MinID = t.IDGen.MinIDAt(ptr.From(req.Since))
+	}
+	if req.Cursor != nil {
+		filter.MinID = max(filter.MinID, ptr.From(req.Cursor)+1)
+	}
+
+	taskModels, err := t.TaskRepo.GetTaskList(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -79,11 +105,14 @@ func (t *taskImpl) GetTaskByID(ctx context.Context, taskID int64) (task *entity.
 	return taskPo2Do(taskModel), nil
 }
 
-func (t *taskImpl) UpdateTask(ctx context.Context, req *UpdateTaskRequest) error {
+func (t *taskImpl) UpdateTask(ctx context.Context, req *UpdateTaskRequest) (completed bool, err error) {
 	updates := make(map[string]any)
 
-	if req.IsCompleted != nil {
-		updates["is_completed"] = ptr.From(req.IsCompleted)
+	// Completing is a conditional update, so that only the request actually
+	// completing the task reports it. The other fields are written with it.
+	completing := ptr.From(req.IsCompleted)
+	if req.IsCompleted != nil && !completing {
+		updates["is_completed"] = false
 	}
 	if req.Date != nil {
 		updates["due_time"] = ptr.From(req.Date)
@@ -92,15 +121,17 @@ func (t *taskImpl) UpdateTask(ctx context.Context, req *UpdateTaskRequest) error
 		updates["content"] = ptr.From(req.Content)
 	}
 	if req.Priority != nil {
+		if !entity.TaskPriority(ptr.From(req.Priority)).IsValid() {
+			return false, errorx.New(errno.ErrTaskPriorityInvalidCode)
+		}
 		updates["priority"] = ptr.From(req.Priority)
 	}
 
-	err := t.TaskRepo.UpdateTask(ctx, req.TaskID, updates)
-	if err != nil {
-		return err
+	if completing {
+		return t.TaskRepo.CompleteTask(ctx, req.TaskID, updates)
 	}
 
-	return nil
+	return false, t.TaskRepo.UpdateTask(ctx, req.TaskID, updates)
 }
 
 func (t *taskImpl) DeleteTask(ctx context.Context, taskID int64) error {
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	MaxAvatarSize = 5 << 20 // maximum accepted upload, in bytes
+	// MaxAvatarPixels bounds the decoded width*height to a 4K frame, 33 MB as
+	// RGBA. Avatars are rendered at 512px at most.
+	MaxAvatarPixels = 3840 * 2160
+
+	AvatarSizeSmall  = 64
+	AvatarSizeMedium = 128
+	AvatarSizeLarge  = 512
+)
+
+// AvatarSizes are the square edge lengths (px) every uploaded avatar is rendered at.
+var AvatarSizes = []int{AvatarSizeSmall, AvatarSizeMedium, AvatarSizeLarge}
+
+var avatarKeyRegexp = regexp.MustCompile(`^(user_avatar/\d+/\d+)_(\d+)\.(\w+)$`)
+
+// AvatarKey returns the object key of one rendered size of an avatar upload.
+func AvatarKey(userID int64, version int64, size int, ext string) string {
+	return fmt.Sprintf("user_avatar/%d/%d_%d.%s", userID, version, size, ext)
+}
+
+// AvatarKeys expands the icon URI stored on a user (the key of the largest size)
+// into the keys of every rendered size. URIs that don't come from the avatar
+// pipeline, such as the default icon, map every size to themselves.
+func AvatarKeys(iconURI string) map[int]string {
+	keys := make(map[int]string, len(AvatarSizes))
+
+	m := avatarKeyRegexp.FindStringSubmatch(iconURI)
+	for _, size := range AvatarSizes {
+		if m == nil {
+			keys[size] = iconURI
+			continue
+		}
+		keys[size] = m[1] + "_" + strconv.Itoa(size) + "." + m[3]
+	}
+
+	return keys
+}
+
+// IsProcessedAvatar reports whether iconURI was produced by the avatar pipeline.
+func IsProcessedAvatar(iconURI string) bool {
+	return avatarKeyRegexp.MatchString(iconURI)
+}
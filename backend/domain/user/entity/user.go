@@ -1,14 +1,43 @@
 package entity
 
+// Role decides what a user may do beyond managing their own data. Like
+// TaskPriority it is stored as plain text and the allowed values are
+// enforced here.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (r Role) String() string {
+	return string(r)
+}
+
 type User struct {
 	UserID int64
 
-	UniqueName string // unique name
-	Name       string // nickname
-	Email      string // email
-	IconURI    string // avatar URI
-	IconURL    string // avatar URL
+	UniqueName string         // unique name
+	Name       string         // nickname
+	Email      string         // email
+	IconURI    string         // avatar URI
+	IconURL    string         // avatar URL, largest size
+	IconURLs   map[int]string // avatar URLs keyed by edge size in px
+	Role       Role           // role
+	DisabledAt int64          // disable time, 0 while the account is active
 
 	CreatedAt int64 // creation time
 	UpdatedAt int64 // update time
 }
+
+func (u *User) Disabled() bool {
+	return u.DisabledAt > 0
+}
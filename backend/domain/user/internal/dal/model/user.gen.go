@@ -14,6 +14,8 @@ type User struct {
 	Email      string `gorm:"column:email;not null;comment:Email" json:"email"`                                  // Email
 	Password   string `gorm:"column:password;not null;comment:Password (Encrypted)" json:"password"`             // Password (Encrypted)
 	IconURI    string `gorm:"column:icon_uri;not null;comment:User Icon URI" json:"icon_uri"`                    // User Icon URI
+	Role       string `gorm:"column:role;not null;default:user;comment:User Role" json:"role"`                   // User Role
+	DisabledAt int64  `gorm:"column:disabled_at;comment:Disable Time (Milliseconds)" json:"disabled_at"`         // Disable Time (Milliseconds)
 	CreatedAt  int64  `gorm:"column:created_at;not null;comment:Creation Time (Milliseconds)" json:"created_at"` // Creation Time (Milliseconds)
 	UpdatedAt  int64  `gorm:"column:updated_at;not null;comment:Update Time (Milliseconds)" json:"updated_at"`   // Update Time (Milliseconds)
 	DeletedAt  int64  `gorm:"column:deleted_at;comment:Deletion Time (Milliseconds)" json:"deleted_at"`          // Deletion Time (Milliseconds)
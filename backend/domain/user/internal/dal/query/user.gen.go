@@ -34,6 +34,8 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.Email = field.NewString(tableName, "email")
 	_user.Password = field.NewString(tableName, "password")
 	_user.IconURI = field.NewString(tableName, "icon_uri")
+	_user.Role = field.NewString(tableName, "role")
+	_user.DisabledAt = field.NewInt64(tableName, "disabled_at")
 	_user.CreatedAt = field.NewInt64(tableName, "created_at")
 	_user.UpdatedAt = field.NewInt64(tableName, "updated_at")
 	_user.DeletedAt = field.NewInt64(tableName, "deleted_at")
@@ -54,6 +56,8 @@ type user struct {
 	Email      field.String // Email
 	Password   field.String // Password (Encrypted)
 	IconURI    field.String // User Icon URI
+	Role       field.String // User Role
+	DisabledAt field.Int64  // Disable Time (Milliseconds)
 	CreatedAt  field.Int64  // Creation Time (Milliseconds)
 	UpdatedAt  field.Int64  // Update Time (Milliseconds)
 	DeletedAt  field.Int64  // Deletion Time (Milliseconds)
@@ -79,6 +83,8 @@ func (u *user) updateTableName(table string) *user {
 	u.Email = field.NewString(table, "email")
 	u.Password = field.NewString(table, "password")
 	u.IconURI = field.NewString(table, "icon_uri")
+	u.Role = field.NewString(table, "role")
+	u.DisabledAt = field.NewInt64(table, "disabled_at")
 	u.CreatedAt = field.NewInt64(table, "created_at")
 	u.UpdatedAt = field.NewInt64(table, "updated_at")
 	u.DeletedAt = field.NewInt64(table, "deleted_at")
@@ -106,13 +112,15 @@ func (u *user) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *user) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 9)
+	u.fieldMap = make(map[string]field.Expr, 11)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["name"] = u.Name
 	u.fieldMap["unique_name"] = u.UniqueName
 	u.fieldMap["email"] = u.Email
 	u.fieldMap["password"] = u.Password
 	u.fieldMap["icon_uri"] = u.IconURI
+	u.fieldMap["role"] = u.Role
+	u.fieldMap["disabled_at"] = u.DisabledAt
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 	u.fieldMap["deleted_at"] = u.DeletedAt
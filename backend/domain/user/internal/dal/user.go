@@ -3,12 +3,15 @@ package dal
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/model"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/query"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
 )
 
 func NewUserDAO(db *gorm.DB) *UserDAO {
@@ -21,8 +24,16 @@ type UserDAO struct {
 	query *query.Query
 }
 
+// readQuery routes reads to a replica unless ctx asks to read its own writes.
+func (dao *UserDAO) readQuery(ctx context.Context) *query.Query {
+	if orm.UsePrimary(ctx) {
+		return dao.query.WriteDB()
+	}
+	return dao.query
+}
+
 func (dao *UserDAO) GetUsersByEmail(ctx context.Context, email string) (*model.User, bool, error) {
-	user, err := dao.query.User.WithContext(ctx).Where(dao.query.User.Email.Eq(email)).First()
+	user, err := dao.readQuery(ctx).User.WithContext(ctx).Where(dao.query.User.Email.Eq(email)).First()
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, false, nil
 	}
@@ -38,15 +49,14 @@ func (dao *UserDAO) UpdatePassword(ctx context.Context, email, password string)
 	_, err := dao.query.User.WithContext(ctx).Where(
 		dao.query.User.Email.Eq(email),
 	).Updates(map[string]any{
-		"password":    password,
-		"session_key": "", // clear session key
-		"updated_at":  time.Now().UnixMilli(),
+		"password":   password,
+		"updated_at": time.Now().UnixMilli(),
 	})
 	return err
 }
 
 func (dao *UserDAO) GetUserByID(ctx context.Context, userID int64) (*model.User, error) {
-	return dao.query.User.WithContext(ctx).Where(
+	return dao.readQuery(ctx).User.WithContext(ctx).Where(
 		dao.query.User.ID.Eq(userID),
 	).First()
 }
@@ -62,7 +72,7 @@ func (dao *UserDAO) UpdateAvatar(ctx context.Context, userID int64, iconURI stri
 }
 
 func (dao *UserDAO) CheckUniqueNameExist(ctx context.Context, uniqueName string) (bool, error) {
-	_, err := dao.query.User.WithContext(ctx).Select(dao.query.User.ID).Where(
+	_, err := dao.readQuery(ctx).User.WithContext(ctx).Select(dao.query.User.ID).Where(
 		dao.query.User.UniqueName.Eq(uniqueName),
 	).First()
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -87,15 +97,11 @@ func (dao *UserDAO) UpdateProfile(ctx context.Context, userID int64, updates map
 
 func (dao *UserDAO) CheckEmailExist(ctx context.Context, email string) (bool, error) {
 	_, exist, err := dao.GetUsersByEmail(ctx, email)
-	if !exist {
-		return false, nil
-	}
-
 	if err != nil {
 		return false, err
 	}
 
-	return true, nil
+	return exist, nil
 }
 
 // CreateUser Create a new user
@@ -105,7 +111,71 @@ func (dao *UserDAO) CreateUser(ctx context.Context, user *model.User) error {
 
 // GetUsersByIDs Query user information in batches
 func (dao *UserDAO) GetUsersByIDs(ctx context.Context, userIDs []int64) ([]*model.User, error) {
-	return dao.query.User.WithContext(ctx).Where(
+	return dao.readQuery(ctx).User.WithContext(ctx).Where(
 		dao.query.User.ID.In(userIDs...),
 	).Find()
 }
+
+// UserListFilter selects users for administration. IDs are time ordered, so
+// MinID is the pagination cursor.
+type UserListFilter struct {
+	Keyword string // matched against email, unique name and nickname, empty means all
+	MinID   int64  // inclusive lower bound, 0 means none
+	Limit   int    // 0 means no limit
+}
+
+// SearchUsers lists users matching filter in ID order
+func (dao *UserDAO) SearchUsers(ctx context.Context, filter *UserListFilter) ([]*model.User, error) {
+	u := dao.query.User
+	do := dao.readQuery(ctx).User.WithContext(ctx)
+	if filter.Keyword != "" {
+		// gen's Like has no ESCAPE clause, and backslash is no escape
+		// character in SQLite
+		pattern := "%" + likeEscaper.Replace(filter.Keyword) + "%"
+		do = do.Where(field.Or(
+			likeEscaped(u.Email, pattern), likeEscaped(u.UniqueName, pattern), likeEscaped(u.Name, pattern),
+		))
+	}
+	if filter.MinID > 0 {
+		do = do.Where(u.ID.Gte(filter.MinID))
+	}
+	do = do.Order(u.ID)
+	if filter.Limit > 0 {
+		do = do.Limit(filter.Limit)
+	}
+
+	return do.Find()
+}
+
+// likeEscaper escapes the LIKE wildcards of a keyword with '!'.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+func likeEscaped(column field.String, pattern string) field.Expr {
+	return field.NewUnsafeFieldRaw("? LIKE ? ESCAPE '!'", column.RawExpr(), pattern)
+}
+
+func (dao *UserDAO) UpdateRole(ctx context.Context, userID int64, role string) error {
+	_, err := dao.query.User.WithContext(ctx).Where(
+		dao.query.User.ID.Eq(userID),
+	).Updates(map[string]any{
+		"role":       role,
+		"updated_at": time.Now().UnixMilli(),
+	})
+	return err
+}
+
+// UpdateDisabledAt disables the account at disabledAt, 0 enables it again
+func (dao *UserDAO) UpdateDisabledAt(ctx context.Context, userID int64, disabledAt int64) error {
+	var value any
+	if disabledAt > 0 {
+		value = disabledAt
+	}
+
+	_, err := dao.query.User.WithContext(ctx).Where(
+		dao.query.User.ID.Eq(userID),
+	).Updates(map[string]any{
+		"disabled_at": value,
+		"updated_at":  time.Now().UnixMilli(),
+	})
+	return err
+}
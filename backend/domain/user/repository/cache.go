@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/cacheaside"
+)
+
+const (
+	userCacheTTL     = 10 * time.Minute
+	userMissCacheTTL = time.Minute
+)
+
+// cachedUserRepo caches users by ID in front of the database.
+type cachedUserRepo struct {
+	UserRepository
+	cache *cacheaside.Cache[model.User]
+}
+
+func newCachedUserRepo(repo UserRepository, c cache.Cache) UserRepository {
+	return &cachedUserRepo{
+		UserRepository: repo,
+		cache:          cacheaside.New[model.User](c, userCacheTTL, userMissCacheTTL, gorm.ErrRecordNotFound),
+	}
+}
+
+func (r *cachedUserRepo) GetUserByID(ctx context.Context, userID int64) (*model.User, error) {
+	if orm.UsePrimary(ctx) {
+		return r.UserRepository.GetUserByID(ctx, userID)
+	}
+
+	return r.cache.Get(ctx, userCacheKey(userID), func(ctx context.Context) (*model.User, error) {
+		user, err := r.UserRepository.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nothing reading users by ID needs the password hash, keep it out of the cache
+		cached := *user
+		cached.Password = ""
+		return &cached, nil
+	})
+}
+
+func (r *cachedUserRepo) CreateUser(ctx context.Context, user *model.User) error {
+	if err := r.UserRepository.CreateUser(ctx, user); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, userCacheKey(user.ID))
+	return nil
+}
+
+func (r *cachedUserRepo) UpdatePassword(ctx context.Context, email, password string) error {
+	if err := r.UserRepository.UpdatePassword(ctx, email, password); err != nil {
+		return err
+	}
+
+	user, exist, err := r.UserRepository.GetUsersByEmail(orm.WithPrimary(ctx), email)
+	if err == nil && exist {
+		r.cache.Del(ctx, userCacheKey(user.ID))
+	}
+	return nil
+}
+
+func (r *cachedUserRepo) UpdateAvatar(ctx context.Context, userID int64, iconURI string) error {
+	if err := r.UserRepository.UpdateAvatar(ctx, userID, iconURI); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, userCacheKey(userID))
+	return nil
+}
+
+func (r *cachedUserRepo) UpdateProfile(ctx context.Context, userID int64, updates map[string]any) error {
+	if err := r.UserRepository.UpdateProfile(ctx, userID, updates); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, userCacheKey(userID))
+	return nil
+}
+
+func (r *cachedUserRepo) UpdateRole(ctx context.Context, userID int64, role string) error {
+	if err := r.UserRepository.UpdateRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, userCacheKey(userID))
+	return nil
+}
+
+func (r *cachedUserRepo) UpdateDisabledAt(ctx context.Context, userID int64, disabledAt int64) error {
+	if err := r.UserRepository.UpdateDisabledAt(ctx, userID, disabledAt); err != nil {
+		return err
+	}
+
+	r.cache.Del(ctx, userCacheKey(userID))
+	return nil
+}
+
+func userCacheKey(userID int64) string {
+	return fmt.Sprintf("user:info:%d", userID)
+}
@@ -7,12 +7,21 @@ import (
 
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 )
 
-func NewUserRepo(db *gorm.DB) UserRepository {
-	return dal.NewUserDAO(db)
+// NewUserRepo returns the user repository, cached in c when it is not nil.
+func NewUserRepo(db *gorm.DB, c cache.Cache) UserRepository {
+	repo := UserRepository(dal.NewUserDAO(db))
+	if c != nil {
+		repo = newCachedUserRepo(repo, c)
+	}
+
+	return repo
 }
 
+type UserListFilter = dal.UserListFilter
+
 type UserRepository interface {
 	GetUsersByEmail(ctx context.Context, email string) (*model.User, bool, error)
 	UpdatePassword(ctx context.Context, email, password string) error
@@ -23,4 +32,7 @@ type UserRepository interface {
 	CheckEmailExist(ctx context.Context, email string) (bool, error)
 	CreateUser(ctx context.Context, user *model.User) error
 	GetUsersByIDs(ctx context.Context, userIDs []int64) ([]*model.User, error)
+	SearchUsers(ctx context.Context, filter *UserListFilter) ([]*model.User, error)
+	UpdateRole(ctx context.Context, userID int64, role string) error
+	UpdateDisabledAt(ctx context.Context, userID int64, disabledAt int64) error
 }
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/model"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/cache/memory"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/migrate"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/sqlite"
+)
+
+// newTestRepo returns the cached repository on a migrated in-memory database.
+func newTestRepo(t *testing.T) UserRepository {
+	t.Helper()
+
+	db, err := sqlite.Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	m, err := migrate.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewUserRepo(db, memory.New())
+}
+
+func createUsers(t *testing.T, repo UserRepository, emails ...string) {
+	t.Helper()
+
+	for i, email := range emails {
+		err := repo.CreateUser(context.Background(), &model.User{
+			ID:         int64(i + 1),
+			Name:       "user",
+			UniqueName: email,
+			Email:      email,
+			Password:   "hash",
+			Role:       "user",
+		})
+		if err != nil {
+			t.Fatalf("create %s: %v", email, err)
+		}
+	}
+}
+
+func TestUserRepoCreateAndGet(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	createUsers(t, repo, "a@x.com")
+
+	user, err := repo.GetUserByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "a@x.com" {
+		t.Errorf("email = %q, want %q", user.Email, "a@x.com")
+	}
+	if user.Password != "" {
+		t.Error("password hash read through the cache")
+	}
+
+	if _, err := repo.GetUserByID(ctx, 2); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("get unknown user: err = %v, want %v", err, gorm.ErrRecordNotFound)
+	}
+
+	exist, err := repo.CheckEmailExist(ctx, "a@x.com")
+	if err != nil || !exist {
+		t.Errorf("CheckEmailExist = %v, %v, want true", exist, err)
+	}
+}
+
+func TestUserRepoEmailIsUnique(t *testing.T) {
+	repo := newTestRepo(t)
+	createUsers(t, repo, "a@x.com")
+
+	err := repo.CreateUser(context.Background(), &model.User{ID: 2, UniqueName: "other", Email: "a@x.com"})
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Errorf("create duplicated email: err = %v, want %v", err, gorm.ErrDuplicatedKey)
+	}
+}
+
+func TestUserRepoUpdateRoleEvictsCache(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	createUsers(t, repo, "a@x.com")
+
+	if _, err := repo.GetUserByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateRole(ctx, 1, "admin"); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := repo.GetUserByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != "admin" {
+		t.Errorf("role = %q after update, want %q", user.Role, "admin")
+	}
+}
+
+func TestUserRepoSearchUsers(t *testing.T) {
+	repo := newTestRepo(t)
+	createUsers(t, repo, "a_b@x.com", "axb@x.com", "100%@x.com", "1000@x.com", "o!k@x.com")
+
+	tests := []struct {
+		keyword string
+		minID   int64
+		limit   int
+		want    []string
+	}{
+		{keyword: "_", want: []string{"a_b@x.com"}},
+		{keyword: "%", want: []string{"100%@x.com"}},
+		{keyword: "!", want: []string{"o!k@x.com"}},
+		{keyword: "AXB", want: []string{"axb@x.com"}},
+		{keyword: "x.com", minID: 2, limit: 2, want: []string{"axb@x.com", "100%@x.com"}},
+		{want: []string{"a_b@x.com", "axb@x.com", "100%@x.com", "1000@x.com", "o!k@x.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			users, err := repo.SearchUsers(context.Background(), &UserListFilter{Keyword: tt.keyword, MinID: tt.minID, Limit: tt.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, u := range users {
+				got = append(got, u.Email)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SearchUsers(%q) = %v, want %v", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
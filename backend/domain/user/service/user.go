@@ -39,14 +39,29 @@ type ValidateProfileUpdateResponse struct {
 	Msg  string
 }
 
+type SearchUsersRequest struct {
+	Keyword string // matched against email, unique name and nickname
+	Cursor  *int64 // ID of the last user of the previous page
+	Limit   int    // page size, 0 means all
+}
+
 type User interface {
 	// Create creates or registers a new user.
 	Create(ctx context.Context, req *CreateUserRequest) (user *entity.User, err error)
 	Login(ctx context.Context, email, password string) (user *entity.User, err error)
 	ResetPassword(ctx context.Context, email, password string) (err error)
 	GetUserInfo(ctx context.Context, userID int64) (user *entity.User, err error)
-	UpdateAvatar(ctx context.Context, userID int64, ext string, imagePayload []byte) (url string, err error)
+	// GetUserByID is GetUserInfo without the avatar URLs, for callers that
+	// only need the account itself.
+	GetUserByID(ctx context.Context, userID int64) (user *entity.User, err error)
+	UpdateAvatar(ctx context.Context, userID int64, imagePayload []byte) (urls map[int]string, err error)
 	UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (err error)
 	GetUserProfiles(ctx context.Context, userID int64) (user *entity.User, err error)
 	MGetUserProfiles(ctx context.Context, userIDs []int64) (users []*entity.User, err error)
+	SearchUsers(ctx context.Context, req *SearchUsersRequest) (users []*entity.User, err error)
+	// UpdateRole changes the role of the user registered with email.
+	UpdateRole(ctx context.Context, email string, role entity.Role) (user *entity.User, err error)
+	// SetDisabled disables or enables an account, it does not end the
+	// sessions of a disabled user.
+	SetDisabled(ctx context.Context, userID int64, disabled bool) (err error)
 }
@@ -2,14 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/crazyfrankie/frx/errorx"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 
 	uploadEntity "github.com/crazyfrankie/ddd-todolist/backend/domain/upload/entity"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/entity"
@@ -17,7 +18,10 @@ import (
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/internal/dal/model"
 	"github.com/crazyfrankie/ddd-todolist/backend/domain/user/repository"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/imagex"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
 	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ptr"
 	"github.com/crazyfrankie/ddd-todolist/backend/types/errno"
@@ -26,6 +30,7 @@ import (
 type Components struct {
 	IconOSS  storage.Storage
 	IDGen    idgen.IDGenerator
+	Locker   lock.Locker
 	UserRepo repository.UserRepository
 }
 
@@ -40,7 +45,27 @@ func NewUserDomain(ctx context.Context, c *Components) User {
 }
 
 func (u *userImpl) Create(ctx context.Context, req *CreateUserRequest) (user *entity.User, err error) {
-	exist, err := u.UserRepo.CheckEmailExist(ctx, req.Email)
+	email := normalizeEmail(req.Email)
+
+	// The email check and the insert are not atomic, serialize registrations of
+	// one email. The unique index on email still backs this up when the lock
+	// expires before the insert.
+	l, err := u.Locker.TryLock(ctx, registerLockKey(email), registerLockTTL)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil, errorx.New(errno.ErrRegisterInProgressCode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock email error: %w", err)
+	}
+	defer func() {
+		if err := l.Release(ctx); err != nil {
+			logs.CtxWarnf(ctx, "release register lock failed, err=%v", err)
+		}
+	}()
+
+	// A replica lagging behind a registration that just finished would let
+	// the email through
+	exist, err := u.UserRepo.CheckEmailExist(orm.WithPrimary(ctx), email)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +91,7 @@ func (u *userImpl) Create(ctx context.Context, req *CreateUserRequest) (user *en
 
 	name := req.Name
 	if name == "" {
-		name = strings.Split(req.Email, "@")[0]
+		name = strings.Split(email, "@")[0]
 	}
 
 	userID, err := u.IDGen.GenID(ctx)
@@ -79,25 +104,32 @@ func (u *userImpl) Create(ctx context.Context, req *CreateUserRequest) (user *en
 	newUser := &model.User{
 		ID:         userID,
 		Name:       name,
-		UniqueName: u.getUniqueNameFormEmail(ctx, req.Email),
-		Email:      req.Email,
+		UniqueName: u.getUniqueNameFormEmail(ctx, email),
+		Email:      email,
 		Password:   hashedPasswd,
 		IconURI:    uploadEntity.UserIconURI,
+		Role:       entity.RoleUser.String(),
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
 
 	err = u.UserRepo.CreateUser(ctx, newUser)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// unique_name is indexed too, only report the email when it is the one taken
+		if exist, _ := u.UserRepo.CheckEmailExist(orm.WithPrimary(ctx), email); exist {
+			return nil, errorx.New(errno.ErrEmailExistCode)
+		}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("insert user failed: %w", err)
 	}
 
-	iconURL, err := u.IconOSS.GetObjectUrl(ctx, newUser.IconURI)
+	iconURLs, err := u.getAvatarURLs(ctx, newUser.IconURI)
 	if err != nil {
 		return nil, fmt.Errorf("get icon url failed: %w", err)
 	}
 
-	return userPo2Do(newUser, iconURL), nil
+	return userPo2Do(newUser, iconURLs), nil
 }
 
 func (u *userImpl) getUniqueNameFormEmail(ctx context.Context, email string) string {
@@ -124,7 +156,7 @@ func (u *userImpl) getUniqueNameFormEmail(ctx context.Context, email string) str
 }
 
 func (u *userImpl) Login(ctx context.Context, email, password string) (user *entity.User, err error) {
-	userModel, exist, err := u.UserRepo.GetUsersByEmail(ctx, email)
+	userModel, exist, err := u.UserRepo.GetUsersByEmail(ctx, normalizeEmail(email))
 	if err != nil {
 		return nil, err
 	}
@@ -136,13 +168,17 @@ func (u *userImpl) Login(ctx context.Context, email, password string) (user *ent
 	if err != nil {
 		return nil, err
 	}
+	// Checked after the password so that it does not tell which emails are registered
+	if userModel.DisabledAt > 0 {
+		return nil, errorx.New(errno.ErrAccountDisabledCode)
+	}
 
-	resURL, err := u.IconOSS.GetObjectUrl(ctx, userModel.IconURI)
+	resURLs, err := u.getAvatarURLs(ctx, userModel.IconURI)
 	if err != nil {
 		return nil, err
 	}
 
-	return userPo2Do(userModel, resURL), nil
+	return userPo2Do(userModel, resURLs), nil
 }
 
 func (u *userImpl) ResetPassword(ctx context.Context, email, password string) (err error) {
@@ -151,7 +187,7 @@ func (u *userImpl) ResetPassword(ctx context.Context, email, password string) (e
 		return err
 	}
 
-	err = u.UserRepo.UpdatePassword(ctx, email, hashedPassword)
+	err = u.UserRepo.UpdatePassword(ctx, normalizeEmail(email), hashedPassword)
 	if err != nil {
 		return err
 	}
@@ -169,32 +205,114 @@ func (u *userImpl) GetUserInfo(ctx context.Context, userID int64) (user *entity.
 		return nil, err
 	}
 
-	resURL, err := u.IconOSS.GetObjectUrl(ctx, userModel.IconURI)
+	resURLs, err := u.getAvatarURLs(ctx, userModel.IconURI)
 	if err != nil {
 		return nil, err
 	}
 
-	return userPo2Do(userModel, resURL), nil
+	return userPo2Do(userModel, resURLs), nil
 }
 
-func (u *userImpl) UpdateAvatar(ctx context.Context, userID int64, ext string, imagePayload []byte) (url string, err error) {
-	avatarKey := "user_avatar/" + strconv.FormatInt(userID, 10) + "." + ext
-	err = u.IconOSS.PutObject(ctx, avatarKey, imagePayload)
+func (u *userImpl) GetUserByID(ctx context.Context, userID int64) (user *entity.User, err error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	userModel, err := u.UserRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return userPo2Do(userModel, nil), nil
+}
+
+func (u *userImpl) UpdateAvatar(ctx context.Context, userID int64, imagePayload []byte) (urls map[int]string, err error) {
+	if len(imagePayload) > uploadEntity.MaxAvatarSize {
+		return nil, errorx.New(errno.ErrAvatarTooLargeCode)
 	}
 
-	err = u.UserRepo.UpdateAvatar(ctx, userID, avatarKey)
+	// Trust the bytes, not the client supplied Content-Type
+	format, err := imagex.Sniff(imagePayload)
 	if err != nil {
-		return "", err
+		return nil, errorx.New(errno.ErrAvatarInvalidCode)
 	}
 
-	url, err = u.IconOSS.GetObjectUrl(ctx, avatarKey)
+	// Decoding keeps only the pixels, so EXIF (GPS, device info...) is dropped on re-encode
+	img, _, err := imagex.Decode(imagePayload, uploadEntity.MaxAvatarPixels)
 	if err != nil {
-		return "", err
+		logs.CtxWarnf(ctx, "decode avatar failed, uid=%d, format=%s, err=%v", userID, format, err)
+		return nil, errorx.New(errno.ErrAvatarInvalidCode)
+	}
+
+	oldUser, err := u.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	version := time.Now().UnixMilli()
+	var iconURI string
+	for _, size := range uploadEntity.AvatarSizes {
+		content, ext, mimeType, err := imagex.Encode(imagex.Square(img, size), format)
+		if err != nil {
+			return nil, err
+		}
+
+		key := uploadEntity.AvatarKey(userID, version, size, ext)
+		err = u.IconOSS.PutObject(ctx, key, content, storage.WithContentType(mimeType))
+		if err != nil {
+			return nil, err
+		}
+
+		if size == uploadEntity.AvatarSizeLarge {
+			iconURI = key
+		}
+	}
+
+	err = u.UserRepo.UpdateAvatar(ctx, userID, iconURI)
+	if err != nil {
+		return nil, err
+	}
+
+	u.deleteAvatar(ctx, oldUser.IconURI)
+
+	return u.getAvatarURLs(ctx, iconURI)
+}
+
+// deleteAvatar removes every rendered size of a replaced avatar. Failures only
+// leave orphan objects behind, so they are logged instead of returned.
+func (u *userImpl) deleteAvatar(ctx context.Context, iconURI string) {
+	if !uploadEntity.IsProcessedAvatar(iconURI) {
+		return
+	}
+
+	for _, key := range uploadEntity.AvatarKeys(iconURI) {
+		if err := u.IconOSS.DeleteObject(ctx, key); err != nil {
+			logs.CtxWarnf(ctx, "delete old avatar %s failed: %v", key, err)
+		}
+	}
+}
+
+func (u *userImpl) getDefaultAvatarURLs(ctx context.Context) map[int]string {
+	urls, err := u.getAvatarURLs(ctx, uploadEntity.UserIconURI)
+	if err != nil {
+		logs.CtxWarnf(ctx, "get default avatar urls failed, err=%v", err)
+		return map[int]string{}
 	}
 
-	return url, nil
+	return urls
+}
+
+func (u *userImpl) getAvatarURLs(ctx context.Context, iconURI string) (map[int]string, error) {
+	urls := make(map[int]string, len(uploadEntity.AvatarSizes))
+	for size, key := range uploadEntity.AvatarKeys(iconURI) {
+		url, err := u.IconOSS.GetObjectUrl(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		urls[size] = url
+	}
+
+	return urls, nil
 }
 
 func (u *userImpl) ValidateProfileUpdate(ctx context.Context, req *ValidateProfileUpdateRequest) (
@@ -284,18 +402,95 @@ func (u *userImpl) MGetUserProfiles(ctx context.Context, userIDs []int64) (users
 		return nil, err
 	}
 
-	users = make([]*userEntity.User, 0, len(userModels))
+	return u.usersPo2Do(ctx, userModels), nil
+}
+
+func (u *userImpl) SearchUsers(ctx context.Context, req *SearchUsersRequest) (users []*entity.User, err error) {
+	filter := &repository.UserListFilter{
+		Keyword: strings.TrimSpace(req.Keyword),
+		Limit:   req.Limit,
+	}
+	if req.Cursor != nil {
+		filter.MinID = ptr.From(req.Cursor) + 1
+	}
+
+	userModels, err := u.UserRepo.SearchUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.usersPo2Do(ctx, userModels), nil
+}
+
+func (u *userImpl) UpdateRole(ctx context.Context, email string, role entity.Role) (user *entity.User, err error) {
+	if !role.IsValid() {
+		return nil, fmt.Errorf("invalid role %q", role)
+	}
+
+	userModel, exist, err := u.UserRepo.GetUsersByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errorx.New(errno.ErrUserNotFoundCode)
+	}
+
+	err = u.UserRepo.UpdateRole(ctx, userModel.ID, role.String())
+	if err != nil {
+		return nil, err
+	}
+	userModel.Role = role.String()
+
+	return userPo2Do(userModel, nil), nil
+}
+
+func (u *userImpl) SetDisabled(ctx context.Context, userID int64, disabled bool) (err error) {
+	userModel, err := u.UserRepo.GetUserByID(orm.WithPrimary(ctx), userID)
+	if err != nil {
+		return err
+	}
+	if (userModel.DisabledAt > 0) == disabled {
+		return nil
+	}
+
+	var disabledAt int64
+	if disabled {
+		disabledAt = time.Now().UnixMilli()
+	}
+
+	return u.UserRepo.UpdateDisabledAt(ctx, userID, disabledAt)
+}
+
+// usersPo2Do resolves the avatars of users, degrading to the default icon
+// rather than dropping a user whose avatar cannot be resolved.
+func (u *userImpl) usersPo2Do(ctx context.Context, userModels []*model.User) []*entity.User {
+	users := make([]*userEntity.User, 0, len(userModels))
 	for _, um := range userModels {
 		// Get image URL
-		resURL, err := u.IconOSS.GetObjectUrl(ctx, um.IconURI)
+		resURLs, err := u.getAvatarURLs(ctx, um.IconURI)
 		if err != nil {
-			continue // If getting the image URL fails, skip the user
+			// Degrade to the default icon rather than dropping the user from the result
+			logs.CtxWarnf(ctx, "get avatar urls failed, uid=%d, err=%v", um.ID, err)
+			resURLs = u.getDefaultAvatarURLs(ctx)
 		}
 
-		users = append(users, userPo2Do(um, resURL))
+		users = append(users, userPo2Do(um, resURLs))
 	}
 
-	return users, nil
+	return users
+}
+
+// registerLockTTL only has to cover the email check, password hashing and the insert.
+const registerLockTTL = 10 * time.Second
+
+func registerLockKey(email string) string {
+	return "user:register:" + email
+}
+
+// normalizeEmail is applied before an email is stored or looked up, so that
+// addresses differing only in case or surrounding spaces are one account.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 func hashPassword(password string) (string, error) {
@@ -312,14 +507,17 @@ func verifyPassword(password, encodedHash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
 }
 
-func userPo2Do(model *model.User, iconURL string) *userEntity.User {
+func userPo2Do(model *model.User, iconURLs map[int]string) *userEntity.User {
 	return &userEntity.User{
 		UserID:     model.ID,
 		Name:       model.Name,
 		UniqueName: model.UniqueName,
 		Email:      model.Email,
 		IconURI:    model.IconURI,
-		IconURL:    iconURL,
+		IconURL:    iconURLs[uploadEntity.AvatarSizeLarge],
+		IconURLs:   iconURLs,
+		Role:       userEntity.Role(model.Role),
+		DisabledAt: model.DisabledAt,
 		CreatedAt:  model.CreatedAt,
 		UpdatedAt:  model.UpdatedAt,
 	}
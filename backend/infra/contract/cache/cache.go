@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the key does not exist or has expired.
+var ErrNotFound = errors.New("cache: key not found")
+
+// Cache is a string key-value store with expiring keys and counters. A zero
+// ttl keeps the key until it is deleted.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// SetNX sets key only if it does not exist and reports whether it did.
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+
+	// IncrBy adds n to the counter at key, creating it at zero, and returns
+	// the new value.
+	IncrBy(ctx context.Context, key string, n int64) (int64, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+}
@@ -2,9 +2,27 @@ package idgen
 
 import (
 	"context"
+	"time"
 )
 
 type IDGenerator interface {
 	GenID(ctx context.Context) (int64, error)
 	GenMultiIDs(ctx context.Context, counts int) ([]int64, error) // suggest batch size <= 200
+	// DecodeID splits an ID back into the parts it was generated from.
+	DecodeID(id int64) (*IDInfo, error)
+	// MinIDAt returns the smallest ID that can be generated at t, so IDs can be
+	// used as a creation time lower bound without a separate column.
+	MinIDAt(t time.Time) int64
+}
+
+// Pinger is implemented by generators that can become unable to generate IDs,
+// such as one whose worker id lease is lost.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type IDInfo struct {
+	Time     time.Time // millisecond precision
+	Sequence int64     // counter within the millisecond
+	ServerID int64     // server id or snowflake worker id
 }
@@ -0,0 +1,60 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotAcquired is returned by TryLock when the key is held by someone else.
+	ErrNotAcquired = errors.New("lock: not acquired")
+	// ErrNotHeld is returned when a lock expired and may be held by someone else.
+	ErrNotHeld = errors.New("lock: not held")
+)
+
+// Locker hands out mutually exclusive, expiring locks on keys.
+type Locker interface {
+	// TryLock acquires key for ttl without waiting.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is a held lock. It expires after its ttl unless refreshed, so work that
+// may outlive it must Refresh periodically.
+type Lock interface {
+	// Refresh extends the lock to ttl from now.
+	Refresh(ctx context.Context, ttl time.Duration) error
+	// Release frees the lock if it is still held by the caller.
+	Release(ctx context.Context) error
+}
+
+// KeepAlive refreshes l to ttl every third of it until stop is called, for
+// work that may outlive ttl. A failed refresh is retried on the next tick,
+// the lock expires on its own once they keep failing.
+func KeepAlive(ctx context.Context, l Lock, ttl time.Duration) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := l.Refresh(ctx, ttl); errors.Is(err, ErrNotHeld) {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
@@ -0,0 +1,17 @@
+package orm
+
+import "context"
+
+type usePrimaryKey struct{}
+
+// WithPrimary marks ctx so that reads made with it are served by the primary
+// instead of a replica. Use it right after a write whose result must be visible.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, usePrimaryKey{}, true)
+}
+
+// UsePrimary reports whether reads made with ctx must go to the primary.
+func UsePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(usePrimaryKey{}).(bool)
+	return v
+}
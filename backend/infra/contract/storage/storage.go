@@ -9,6 +9,12 @@ type Storage interface {
 	GetObjectUrl(ctx context.Context, objectKey string, opts ...GetOptFn) (string, error)
 }
 
+// Pinger is implemented by backends that can tell whether their bucket is
+// reachable without touching any object.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 type SecurityToken struct {
 	AccessKeyID     string `json:"access_key_id"`
 	SecretAccessKey string `json:"secret_access_key"`
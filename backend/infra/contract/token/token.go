@@ -9,9 +9,11 @@ import (
 type JWT interface {
 	GetAccessToken(c *gin.Context) (string, error)
 	GenerateToken(uid int64, ua string) ([]string, error)
-	ParseToken(token string) (*Claims, error)
-	TryRefresh(refresh string, ua string) ([]string, *Claims, error)
+	ParseToken(ctx context.Context, token string) (*Claims, error)
+	TryRefresh(ctx context.Context, refresh string, ua string) ([]string, *Claims, error)
 	CleanToken(ctx context.Context, uid int64, ua string) error
+	// RevokeAll invalidates every token issued to uid so far, on every device.
+	RevokeAll(ctx context.Context, uid int64) error
 }
 
 type Claims struct {
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+)
+
+const sweepInterval = time.Minute
+
+// New returns a cache held in process memory, for single node deployments
+// that run without Redis. Expired keys are dropped lazily on access and by a
+// periodic sweep.
+func New() cache.Cache {
+	return &memoryCache{
+		items:     make(map[string]item),
+		lastSweep: time.Now(),
+	}
+}
+
+type item struct {
+	value    string
+	expireAt time.Time // zero means no expiration
+}
+
+func (i item) expired(now time.Time) bool {
+	return !i.expireAt.IsZero() && !now.Before(i.expireAt)
+}
+
+type memoryCache struct {
+	mu        sync.Mutex
+	items     map[string]item
+	lastSweep time.Time
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	it, ok := m.load(key, time.Now())
+	if !ok {
+		return "", cache.ErrNotFound
+	}
+
+	return it.value, nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.items[key] = item{value: value, expireAt: expireAt(now, ttl)}
+	m.sweep(now)
+
+	return nil
+}
+
+func (m *memoryCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := m.load(key, now); ok {
+		return false, nil
+	}
+	m.items[key] = item{value: value, expireAt: expireAt(now, ttl)}
+	m.sweep(now)
+
+	return true, nil
+}
+
+func (m *memoryCache) Del(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+
+	return nil
+}
+
+func (m *memoryCache) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	it, _ := m.load(key, now)
+
+	var cur int64
+	if it.value != "" {
+		var err error
+		cur, err = strconv.ParseInt(it.value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value of %s is not an integer", key)
+		}
+	}
+	cur += n
+	it.value = strconv.FormatInt(cur, 10)
+	m.items[key] = it
+	m.sweep(now)
+
+	return cur, nil
+}
+
+func (m *memoryCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if it, ok := m.load(key, now); ok {
+		it.expireAt = expireAt(now, ttl)
+		m.items[key] = it
+	}
+
+	return nil
+}
+
+// load returns the live item under key, dropping it if it has expired.
+func (m *memoryCache) load(key string, now time.Time) (item, bool) {
+	it, ok := m.items[key]
+	if !ok {
+		return item{}, false
+	}
+	if it.expired(now) {
+		delete(m.items, key)
+		return item{}, false
+	}
+
+	return it, true
+}
+
+func (m *memoryCache) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, it := range m.items {
+		if it.expired(now) {
+			delete(m.items, key)
+		}
+	}
+}
+
+func expireAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return now.Add(ttl)
+}
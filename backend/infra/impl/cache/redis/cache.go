@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+)
+
+// NewCache adapts cli to the cache contract.
+func NewCache(cli redis.Cmdable) cache.Cache {
+	return &redisCache{cli: cli}
+}
+
+type redisCache struct {
+	cli redis.Cmdable
+}
+
+func (r *redisCache) Get(ctx context.Context, key string) (string, error) {
+	val, err := r.cli.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", cache.ErrNotFound
+	}
+
+	return val, err
+}
+
+func (r *redisCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return r.cli.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *redisCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return r.cli.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (r *redisCache) Del(ctx context.Context, keys ...string) error {
+	return r.cli.Del(ctx, keys...).Err()
+}
+
+func (r *redisCache) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return r.cli.IncrBy(ctx, key, n).Result()
+}
+
+func (r *redisCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return r.cli.Expire(ctx, key, ttl).Err()
+}
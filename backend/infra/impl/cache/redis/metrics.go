@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
+)
+
+// PoolCollector exports the connection pool statistics of cli. Register it
+// with prometheus.MustRegister.
+func PoolCollector(cli *redis.Client) prometheus.Collector {
+	return &poolCollector{cli: cli}
+}
+
+var (
+	poolHits     = poolDesc("hits_total", "Times a free connection was found in the pool.")
+	poolMisses   = poolDesc("misses_total", "Times a free connection was not found in the pool.")
+	poolTimeouts = poolDesc("timeouts_total", "Times a wait for a connection timed out.")
+	poolTotal    = poolDesc("connections", "Connections in the pool.")
+	poolIdle     = poolDesc("idle_connections", "Idle connections in the pool.")
+	poolStale    = poolDesc("stale_connections_total", "Stale connections removed from the pool.")
+)
+
+func poolDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "redis_pool", name), help, nil, nil)
+}
+
+type poolCollector struct {
+	cli *redis.Client
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolHits
+	ch <- poolMisses
+	ch <- poolTimeouts
+	ch <- poolTotal
+	ch <- poolIdle
+	ch <- poolStale
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := p.cli.PoolStats()
+	ch <- prometheus.MustNewConstMetric(poolHits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(poolMisses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(poolTimeouts, prometheus.CounterValue, float64(s.Timeouts))
+	ch <- prometheus.MustNewConstMetric(poolTotal, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(poolIdle, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(poolStale, prometheus.CounterValue, float64(s.StaleConns))
+}
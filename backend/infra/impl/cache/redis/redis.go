@@ -3,6 +3,7 @@ package redis
 import (
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/conf"
@@ -10,7 +11,8 @@ import (
 
 type Client = redis.Client
 
-func New() *redis.Client {
+// New connects to the configured Redis, commands are recorded as trace spans.
+func New() (*redis.Client, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     conf.GetConf().Redis.Addr, // Redis地址
 		DB:       0,                         // 默认数据库
@@ -27,5 +29,9 @@ func New() *redis.Client {
 		WriteTimeout: 3 * time.Second, // write operation timed out
 	})
 
-	return rdb
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		return nil, err
+	}
+
+	return rdb, nil
 }
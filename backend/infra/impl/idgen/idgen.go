@@ -5,28 +5,34 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/redis/go-redis/v9"
-
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
 )
 
-const (
-	counterKeyExpirationTime = 10 * time.Minute
-	maxCounterPosition       = 255
-)
+const counterKeyExpirationTime = 10 * time.Minute
 
 type IDGenerator = idgen.IDGenerator
 
-func New(client *redis.Client) (idgen.IDGenerator, error) {
-	// Initialization code.
+// New returns a generator that allocates counters in c. With a shared cache
+// such as Redis any number of instances may share one svrID, with an in-process
+// cache every instance needs its own. svrID must not be used by a fixed snowflake
+// worker, leased ones are out of its range.
+func New(c cache.Cache, namespace string, svrID int64) (idgen.IDGenerator, error) {
+	if svrID < 0 || svrID > maxConfiguredID {
+		return nil, fmt.Errorf("server id out of range [0, %d], serverID=%v", maxConfiguredID, svrID)
+	}
+
 	return &idGenImpl{
-		cli: client,
+		cache:     c,
+		namespace: namespace,
+		svrID:     svrID,
 	}, nil
 }
 
 type idGenImpl struct {
-	cli       *redis.Client
+	cache     cache.Cache
 	namespace string
+	svrID     int64
 }
 
 func (i *idGenImpl) GenID(ctx context.Context) (int64, error) {
@@ -44,7 +50,7 @@ func (i *idGenImpl) GenMultiIDs(ctx context.Context, counts int) ([]int64, error
 	leftNum := int64(counts)
 	lastMs := int64(0)
 	ids := make([]int64, 0, counts)
-	svrID := int64(0) // A server id is all 0.
+	svrID := i.svrID
 
 	for idx := int64(0); leftNum > 0 && idx < maxTimeAddrTimes; idx++ {
 		ms := maxInt64(i.GetIDTimeMs(), lastMs)
@@ -81,20 +87,11 @@ func (i *idGenImpl) GenMultiIDs(ctx context.Context, counts int) ([]int64, error
 			leftNum = 0
 		}
 
-		seconds := ms / 1000
-		millis := ms % 1000
-
-		if seconds&0xFFFFFFFF != seconds {
-			return nil, fmt.Errorf("seconds more than 32 bits, seconds=%v", seconds)
-		}
-
-		if svrID&0x3FFF != svrID {
-			return nil, fmt.Errorf("server id more than 14 bits, serverID=%v", svrID)
-		}
-
 		for i := start; i < end; i++ {
-			// fmt.Printf("sec=%v, ms=%v, counter=%v\n", seconds, millis, i)
-			id := (seconds)<<32 + (millis)<<22 + i<<14 + svrID
+			id, err := makeID(ms, i, svrID)
+			if err != nil {
+				return nil, err
+			}
 			ids = append(ids, id)
 		}
 	}
@@ -106,8 +103,16 @@ func (i *idGenImpl) GenMultiIDs(ctx context.Context, counts int) ([]int64, error
 	return ids, nil
 }
 
+func (i *idGenImpl) DecodeID(id int64) (*idgen.IDInfo, error) {
+	return Decode(id)
+}
+
+func (i *idGenImpl) MinIDAt(t time.Time) int64 {
+	return MinIDAt(t)
+}
+
 func (i *idGenImpl) IncrBy(ctx context.Context, key string, num int64) (cntPos int64, err error) {
-	return i.cli.IncrBy(ctx, key, num).Result()
+	return i.cache.IncrBy(ctx, key, num)
 }
 
 func (i *idGenImpl) GetIDTimeMs() int64 {
@@ -116,7 +121,7 @@ func (i *idGenImpl) GetIDTimeMs() int64 {
 
 func (i *idGenImpl) Expire(ctx context.Context, key string) {
 	// Temporarily ignore errors
-	_, _ = i.cli.Expire(ctx, key, counterKeyExpirationTime).Result()
+	_ = i.cache.Expire(ctx, key, counterKeyExpirationTime)
 }
 
 func genIDKey(space string, svrID int64, ms int64) string {
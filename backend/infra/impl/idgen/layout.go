@@ -0,0 +1,70 @@
+package idgen
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+)
+
+const (
+	counterBits  = 12
+	serverIDBits = 10
+
+	maxCounterPosition = 1<<counterBits - 1
+	maxServerID        = 1<<serverIDBits - 1
+
+	// leasedWorkerIDBase splits the server ids: configured ones, the Redis
+	// generator's server id and fixed snowflake worker ids, are below it and
+	// leased snowflake worker ids from it up, so the two never collide.
+	leasedWorkerIDBase = 1 << (serverIDBits - 1)
+	maxConfiguredID    = leasedWorkerIDBase - 1
+)
+
+// makeID packs an ID as | 32 bits seconds | 10 bits millis | 12 bits counter | 10 bits server id |.
+// Both generators share this layout, so their IDs live in one space and stay time ordered.
+// The seconds and millis bits are where they were with the earlier 8 bits counter and
+// 14 bits server id, so IDs made before and after the change still sort by time.
+func makeID(ms int64, counter int64, svrID int64) (int64, error) {
+	seconds := ms / 1000
+	millis := ms % 1000
+
+	if seconds&0xFFFFFFFF != seconds {
+		return 0, fmt.Errorf("seconds more than 32 bits, seconds=%v", seconds)
+	}
+
+	if svrID&maxServerID != svrID {
+		return 0, fmt.Errorf("server id more than %d bits, serverID=%v", serverIDBits, svrID)
+	}
+
+	return seconds<<32 + millis<<22 + counter<<serverIDBits + svrID, nil
+}
+
+// Decode splits an ID produced by any generator of this package.
+func Decode(id int64) (*idgen.IDInfo, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id %d", id)
+	}
+
+	seconds := id >> 32
+	millis := (id >> 22) & 0x3FF
+	if millis >= 1000 {
+		return nil, fmt.Errorf("invalid id %d, millis=%d", id, millis)
+	}
+
+	return &idgen.IDInfo{
+		Time:     time.UnixMilli(seconds*1000 + millis),
+		Sequence: (id >> serverIDBits) & maxCounterPosition,
+		ServerID: id & maxServerID,
+	}, nil
+}
+
+// MinIDAt returns the smallest ID any generator of this package can produce at t.
+func MinIDAt(t time.Time) int64 {
+	ms := t.UnixMilli()
+	if ms <= 0 {
+		return 0
+	}
+
+	return (ms/1000)<<32 + (ms%1000)<<22
+}
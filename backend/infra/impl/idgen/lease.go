@@ -0,0 +1,208 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+const (
+	workerLeaseTTL       = 30 * time.Second
+	workerLeaseHeartbeat = workerLeaseTTL / 3
+	// workerLeaseMargin is taken off the TTL before a lease counts as lost,
+	// it covers the clocks of Redis and this process running at different rates.
+	workerLeaseMargin = 2 * time.Second
+)
+
+const (
+	renewTakenOver = 0
+	renewRenewed   = 1
+	renewRecovered = 2
+)
+
+var (
+	// acquireLeaseScript takes the first free worker id of the count ids from
+	// base, after a random offset, in a single round trip. Keys are built in
+	// the script, so it needs a standalone Redis rather than a cluster.
+	acquireLeaseScript = redis.NewScript(`
+local base = tonumber(ARGV[3])
+local count = tonumber(ARGV[4])
+local start = tonumber(ARGV[5])
+for i = 0, count - 1 do
+	local id = base + (start + i) % count
+	if redis.call("SET", ARGV[1] .. id, ARGV[2], "NX", "PX", ARGV[6]) then
+		return id
+	end
+end
+return 0`)
+
+	// renewLeaseScript extends the lease, or takes the same id back when it
+	// expired while Redis was unreachable and nobody else claimed it.
+	renewLeaseScript = redis.NewScript(`
+local owner = redis.call("GET", KEYS[1])
+if owner == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+if not owner then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 2
+end
+return 0`)
+
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// workerLease owns a worker id in Redis for as long as its heartbeat keeps
+// renewing it. Once a renewal is missed for a whole TTL the id may already be
+// owned by another instance, so the lease reports itself invalid until the
+// heartbeat gets hold of an id again: the same one if it expired unclaimed,
+// a newly leased one if it was taken over.
+type workerLease struct {
+	cli       *redis.Client
+	namespace string
+	token     string
+
+	mu        sync.Mutex
+	key       string
+	workerID  int64
+	lost      bool
+	renewedAt time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func leaseWorkerID(ctx context.Context, cli *redis.Client, namespace string) (*workerLease, error) {
+	l := &workerLease{
+		cli:       cli,
+		namespace: namespace,
+		token:     uuid.NewString(),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	go l.heartbeat()
+
+	return l, nil
+}
+
+// acquire leases a free worker id, from leasedWorkerIDBase up so that it
+// never is a configured server or worker id.
+func (l *workerLease) acquire(ctx context.Context) error {
+	const count = maxServerID - leasedWorkerIDBase + 1
+
+	sentAt := time.Now()
+	id, err := acquireLeaseScript.Run(ctx, l.cli, nil,
+		workerLeaseKey(l.namespace, ""), l.token, leasedWorkerIDBase, count, rand.Int64N(count), workerLeaseTTL.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return fmt.Errorf("lease worker id failed: %w", err)
+	}
+	if id == 0 {
+		return fmt.Errorf("no free worker id in namespace %q", l.namespace)
+	}
+
+	l.mu.Lock()
+	l.key, l.workerID, l.lost, l.renewedAt = workerLeaseKey(l.namespace, id), id, false, sentAt
+	l.mu.Unlock()
+
+	logs.Infof("leased snowflake worker id %d", id)
+	return nil
+}
+
+func (l *workerLease) heartbeat() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(workerLeaseHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.renew()
+		}
+	}
+}
+
+func (l *workerLease) renew() {
+	ctx, cancel := context.WithTimeout(context.Background(), workerLeaseHeartbeat)
+	defer cancel()
+
+	l.mu.Lock()
+	key, lost := l.key, l.lost
+	l.mu.Unlock()
+
+	if lost {
+		if err := l.acquire(ctx); err != nil {
+			logs.Errorf("re-lease worker id failed, err=%v", err)
+		}
+		return
+	}
+
+	// Redis starts the new TTL some time after this, counting from here
+	// never believes the lease lasts longer than it does
+	sentAt := time.Now()
+	res, err := renewLeaseScript.Run(ctx, l.cli, []string{key}, l.token, workerLeaseTTL.Milliseconds()).Int64()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case err != nil:
+		logs.Warnf("renew worker id lease failed, key=%s, err=%v", key, err)
+	case res == renewTakenOver:
+		// Re-leased on the next tick, the id must not be used meanwhile
+		l.lost = true
+		logs.Errorf("worker id lease taken over, key=%s", key)
+	case res == renewRecovered:
+		l.renewedAt = sentAt
+		logs.Warnf("worker id lease expired and was recovered, key=%s", key)
+	case res == renewRenewed:
+		l.renewedAt = sentAt
+	}
+}
+
+// current returns the worker id to stamp into IDs, false while no id is
+// safely owned.
+func (l *workerLease) current() (int64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.workerID, !l.lost && time.Since(l.renewedAt) < workerLeaseTTL-workerLeaseMargin
+}
+
+func (l *workerLease) release() error {
+	l.closeOnce.Do(func() { close(l.stop) })
+	<-l.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), workerLeaseHeartbeat)
+	defer cancel()
+
+	l.mu.Lock()
+	key := l.key
+	l.mu.Unlock()
+
+	return releaseLeaseScript.Run(ctx, l.cli, []string{key}, l.token).Err()
+}
+
+// workerLeaseKey returns the key of workerID, the key prefix for an empty
+// workerID.
+func workerLeaseKey(namespace string, workerID any) string {
+	return fmt.Sprintf("id_generator_worker:%v:%v", namespace, workerID)
+}
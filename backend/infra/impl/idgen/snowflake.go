@@ -0,0 +1,169 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/idgen"
+)
+
+const (
+	// maxAheadMs is how far the generator may run ahead of the wall clock, either
+	// by borrowing future milliseconds once a millisecond's counter is exhausted
+	// or because the clock moved backwards, before callers have to wait.
+	maxAheadMs = 1000
+	// maxWaitMs bounds the wait for the clock to catch up. A larger gap means the
+	// clock was rolled back too far and generation fails instead of blocking.
+	maxWaitMs = 5000
+)
+
+var ErrWorkerLeaseLost = errors.New("snowflake worker id lease lost")
+
+// Snowflake generates IDs purely in process. It does not talk to Redis on the
+// hot path and keeps the same layout as the Redis generator, with the worker
+// id stored in the server id bits.
+type Snowflake struct {
+	mu       sync.Mutex
+	workerID int64
+	lastMs   int64
+	counter  int64
+
+	lease *workerLease // nil when the worker id comes from config
+}
+
+var _ idgen.IDGenerator = (*Snowflake)(nil)
+
+// NewSnowflake creates a generator for workerID. With workerID 0 a free worker
+// id above the configurable range is leased from Redis and kept alive with a heartbeat until Close, the
+// worker id changes if the lease is ever taken over.
+func NewSnowflake(ctx context.Context, cli *redis.Client, namespace string, workerID int64) (*Snowflake, error) {
+	if workerID < 0 || workerID > maxConfiguredID {
+		return nil, fmt.Errorf("worker id out of range [0, %d], workerID=%v", maxConfiguredID, workerID)
+	}
+
+	s := &Snowflake{workerID: workerID}
+	if workerID != 0 {
+		return s, nil
+	}
+
+	if cli == nil {
+		return nil, errors.New("leasing a worker id requires redis")
+	}
+
+	lease, err := leaseWorkerID(ctx, cli, namespace)
+	if err != nil {
+		return nil, err
+	}
+	s.lease = lease
+	s.workerID = lease.workerID
+
+	return s, nil
+}
+
+// WorkerID returns the worker id stamped into generated IDs.
+func (s *Snowflake) WorkerID() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.workerID
+}
+
+// Ping reports whether IDs can be generated, which is not the case while a
+// leased worker id is not safely owned.
+func (s *Snowflake) Ping(context.Context) error {
+	if s.lease == nil {
+		return nil
+	}
+	if _, ok := s.lease.current(); !ok {
+		return ErrWorkerLeaseLost
+	}
+
+	return nil
+}
+
+func (s *Snowflake) GenID(ctx context.Context) (int64, error) {
+	ids, err := s.GenMultiIDs(ctx, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+func (s *Snowflake) GenMultiIDs(ctx context.Context, counts int) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lease != nil {
+		workerID, ok := s.lease.current()
+		if !ok {
+			return nil, ErrWorkerLeaseLost
+		}
+		// a lease taken over is replaced by one on another worker id
+		s.workerID = workerID
+	}
+
+	ids := make([]int64, 0, counts)
+	for len(ids) < counts {
+		now := time.Now().UnixMilli()
+		if now > s.lastMs {
+			s.lastMs, s.counter = now, 0
+		}
+		if s.counter > maxCounterPosition {
+			s.lastMs, s.counter = s.lastMs+1, 0
+		}
+
+		if ahead := s.lastMs - now; ahead > maxAheadMs {
+			if ahead > maxWaitMs {
+				return nil, fmt.Errorf("clock moved backwards, refusing to generate ids for %dms", ahead)
+			}
+			if err := sleepCtx(ctx, time.Duration(ahead-maxAheadMs)*time.Millisecond); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		id, err := makeID(s.lastMs, s.counter, s.workerID)
+		if err != nil {
+			return nil, err
+		}
+		s.counter++
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *Snowflake) DecodeID(id int64) (*idgen.IDInfo, error) {
+	return Decode(id)
+}
+
+func (s *Snowflake) MinIDAt(t time.Time) int64 {
+	return MinIDAt(t)
+}
+
+// Close releases the leased worker id, if any.
+func (s *Snowflake) Close() error {
+	if s.lease == nil {
+		return nil
+	}
+
+	return s.lease.release()
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+package idgen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	rediscache "github.com/crazyfrankie/ddd-todolist/backend/infra/impl/cache/redis"
+)
+
+func newRedis(tb testing.TB) (*miniredis.Miniredis, *redis.Client) {
+	tb.Helper()
+
+	mr := miniredis.RunT(tb)
+	cli := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	tb.Cleanup(func() { _ = cli.Close() })
+
+	return mr, cli
+}
+
+func TestLeaseRecoversExpiredKey(t *testing.T) {
+	mr, cli := newRedis(t)
+	ctx := context.Background()
+
+	s, err := NewSnowflake(ctx, cli, "test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	workerID := s.WorkerID()
+
+	// Redis unreachable for longer than the TTL: the key expires unclaimed
+	mr.FastForward(2 * workerLeaseTTL)
+	s.lease.mu.Lock()
+	s.lease.renewedAt = time.Now().Add(-2 * workerLeaseTTL)
+	s.lease.mu.Unlock()
+	if _, err := s.GenID(ctx); err != ErrWorkerLeaseLost {
+		t.Fatalf("GenID with an expired lease: err = %v, want %v", err, ErrWorkerLeaseLost)
+	}
+	if err := s.Ping(ctx); err == nil {
+		t.Fatal("Ping with an expired lease succeeded")
+	}
+
+	s.lease.renew()
+
+	if _, err := s.GenID(ctx); err != nil {
+		t.Fatalf("GenID after recovery: %v", err)
+	}
+	if got := s.WorkerID(); got != workerID {
+		t.Errorf("worker id = %d after recovery, want the same %d", got, workerID)
+	}
+}
+
+func TestLeaseTakenOverIsReplaced(t *testing.T) {
+	mr, cli := newRedis(t)
+	ctx := context.Background()
+
+	s, err := NewSnowflake(ctx, cli, "test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	workerID := s.WorkerID()
+
+	if err := mr.Set(workerLeaseKey("test", workerID), "someone else"); err != nil {
+		t.Fatal(err)
+	}
+	s.lease.renew()
+	if _, err := s.GenID(ctx); err != ErrWorkerLeaseLost {
+		t.Fatalf("GenID with a lease taken over: err = %v, want %v", err, ErrWorkerLeaseLost)
+	}
+
+	s.lease.renew()
+
+	id, err := s.GenID(ctx)
+	if err != nil {
+		t.Fatalf("GenID after re-leasing: %v", err)
+	}
+	info, _ := Decode(id)
+	if info.ServerID == workerID {
+		t.Errorf("id %d still uses the worker id %d that was taken over", id, workerID)
+	}
+	if got, _ := mr.Get(workerLeaseKey("test", workerID)); got != "someone else" {
+		t.Errorf("worker id %d owner = %q, the new owner must keep it", workerID, got)
+	}
+}
+
+func TestLeaseSkipsTakenWorkerIDs(t *testing.T) {
+	_, cli := newRedis(t)
+	ctx := context.Background()
+
+	seen := make(map[int64]bool)
+	for range 50 {
+		s, err := NewSnowflake(ctx, cli, "test", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		if seen[s.WorkerID()] {
+			t.Fatalf("worker id %d leased twice", s.WorkerID())
+		}
+		if s.WorkerID() < leasedWorkerIDBase || s.WorkerID() > maxServerID {
+			t.Fatalf("leased worker id %d out of [%d, %d]", s.WorkerID(), leasedWorkerIDBase, maxServerID)
+		}
+		seen[s.WorkerID()] = true
+	}
+}
+
+func TestSnowflakeLayout(t *testing.T) {
+	s, err := NewSnowflake(context.Background(), nil, "test", maxConfiguredID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := s.GenMultiIDs(context.Background(), 2*(maxCounterPosition+1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range ids {
+		info, err := Decode(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ServerID != maxConfiguredID {
+			t.Fatalf("id %d server id = %d, want %d", id, info.ServerID, maxConfiguredID)
+		}
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("id %d not after %d", id, ids[i-1])
+		}
+	}
+
+	if _, err := NewSnowflake(context.Background(), nil, "test", leasedWorkerIDBase); err == nil {
+		t.Errorf("worker id %d of the leased range accepted", leasedWorkerIDBase)
+	}
+}
+
+func BenchmarkSnowflakeGenID(b *testing.B) {
+	s, err := NewSnowflake(context.Background(), nil, "bench", 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := s.GenID(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRedisGenID runs against an in-process miniredis, a networked Redis
+// adds a round trip per ID on top.
+func BenchmarkRedisGenID(b *testing.B) {
+	_, cli := newRedis(b)
+	g, err := New(rediscache.NewCache(cli), "bench", 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := g.GenID(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
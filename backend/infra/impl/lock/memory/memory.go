@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+)
+
+// New returns a Locker that only excludes holders within this process, for
+// deployments running without Redis.
+func New() lock.Locker {
+	return &locker{held: make(map[string]*memoryLock)}
+}
+
+type locker struct {
+	mu   sync.Mutex
+	held map[string]*memoryLock
+}
+
+type memoryLock struct {
+	l        *locker
+	key      string
+	expireAt time.Time
+}
+
+func (l *locker) TryLock(ctx context.Context, key string, ttl time.Duration) (lock.Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if cur, ok := l.held[key]; ok && now.Before(cur.expireAt) {
+		return nil, lock.ErrNotAcquired
+	}
+
+	ml := &memoryLock{l: l, key: key, expireAt: now.Add(ttl)}
+	l.held[key] = ml
+
+	return ml, nil
+}
+
+func (m *memoryLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	m.l.mu.Lock()
+	defer m.l.mu.Unlock()
+
+	now := time.Now()
+	if m.l.held[m.key] != m || !now.Before(m.expireAt) {
+		return lock.ErrNotHeld
+	}
+	m.expireAt = now.Add(ttl)
+
+	return nil
+}
+
+func (m *memoryLock) Release(ctx context.Context) error {
+	m.l.mu.Lock()
+	defer m.l.mu.Unlock()
+
+	if m.l.held[m.key] != m {
+		return lock.ErrNotHeld
+	}
+	delete(m.l.held, m.key)
+
+	if !time.Now().Before(m.expireAt) {
+		return lock.ErrNotHeld
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/lock"
+)
+
+var (
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// New returns a Locker backed by Redis. Every lock carries a random token so
+// that an expired holder cannot refresh or release a lock taken over by others.
+func New(cli redis.Cmdable) lock.Locker {
+	return &locker{cli: cli}
+}
+
+type locker struct {
+	cli redis.Cmdable
+}
+
+func (l *locker) TryLock(ctx context.Context, key string, ttl time.Duration) (lock.Lock, error) {
+	token := uuid.NewString()
+
+	ok, err := l.cli.SetNX(ctx, lockKey(key), token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, lock.ErrNotAcquired
+	}
+
+	return &redisLock{cli: l.cli, key: lockKey(key), token: token}, nil
+}
+
+type redisLock struct {
+	cli   redis.Cmdable
+	key   string
+	token string
+}
+
+func (r *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := refreshScript.Run(ctx, r.cli, []string{r.key}, r.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return lock.ErrNotHeld
+	}
+
+	return nil
+}
+
+func (r *redisLock) Release(ctx context.Context) error {
+	n, err := releaseScript.Run(ctx, r.cli, []string{r.key}, r.token).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return lock.ErrNotHeld
+	}
+
+	return nil
+}
+
+func lockKey(key string) string {
+	return "lock:" + key
+}
@@ -0,0 +1,310 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrations live in sql/<dialect>/<version>_<name>.{up,down}.sql and are
+// embedded in the binary. Statements are separated by a semicolon at the end
+// of a line.
+//
+// Each migration runs in a transaction, but MySQL commits implicitly after
+// every DDL statement: a MySQL migration failing halfway stays partially
+// applied and unrecorded, and has to be completed or undone by hand before
+// running Up again. Keep MySQL migrations to one DDL statement where possible.
+//
+//go:embed sql
+var migrationFS embed.FS
+
+const tableName = "schema_migrations"
+
+var (
+	ErrSchemaOutdated = errors.New("database schema is out of date")
+
+	fileRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	*Migration
+	Applied   bool
+	AppliedAt int64 // milliseconds
+}
+
+type schemaMigration struct {
+	Version   int64  `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string `gorm:"column:name;not null"`
+	AppliedAt int64  `gorm:"column:applied_at;not null"`
+}
+
+func (*schemaMigration) TableName() string {
+	return tableName
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// New loads the migrations matching db's dialect.
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(dialect string) ([]*Migration, error) {
+	dir := path.Join("sql", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %s: %w", dialect, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		m := fileRegexp.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", e.Name())
+		}
+
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in order.
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, mig.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{
+				Version:   mig.Version,
+				Name:      mig.Name,
+				AppliedAt: time.Now().UnixMilli(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("apply migration %04d_%s failed%s: %w", mig.Version, mig.Name, m.partialHint(), err)
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Down reverts the latest steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, mig.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, mig.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("revert migration %04d_%s failed%s: %w", mig.Version, mig.Name, m.partialHint(), err)
+		}
+
+		done = append(done, mig)
+	}
+
+	return done, nil
+}
+
+// Status lists every migration of this binary, it does not write to the
+// database.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := &Status{Migration: mig}
+		if a, ok := applied[mig.Version]; ok {
+			s.Applied, s.AppliedAt = true, a.AppliedAt
+		}
+		res = append(res, s)
+	}
+
+	return res, nil
+}
+
+// Check fails with ErrSchemaOutdated when a migration of this binary has not
+// been applied, or when the database has been migrated by a newer binary. It
+// does not write to the database.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int64]struct{}, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = struct{}{}
+		if _, ok := applied[mig.Version]; !ok {
+			return fmt.Errorf("%w: migration %04d_%s is pending", ErrSchemaOutdated, mig.Version, mig.Name)
+		}
+	}
+
+	for version, a := range applied {
+		if _, ok := known[version]; !ok {
+			return fmt.Errorf("%w: migration %04d_%s is unknown to this binary", ErrSchemaOutdated, version, a.Name)
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) createTable(ctx context.Context) error {
+	if err := m.db.WithContext(ctx).Migrator().AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("create %s failed: %w", tableName, err)
+	}
+
+	return nil
+}
+
+// applied returns the applied migrations by version, none when the database
+// has never been migrated.
+func (m *Migrator) applied(ctx context.Context) (map[int64]*schemaMigration, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return map[int64]*schemaMigration{}, nil
+	}
+
+	var rows []*schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]*schemaMigration, len(rows))
+	for _, r := range rows {
+		res[r.Version] = r
+	}
+
+	return res, nil
+}
+
+func (m *Migrator) partialHint() string {
+	if m.db.Dialector.Name() == "mysql" {
+		return ", MySQL has committed the statements that ran before the failure"
+	}
+	return ""
+}
+
+func execScript(tx *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var (
+		stmts []string
+		buf   strings.Builder
+	)
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(buf.String()))
+			buf.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(buf.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+
+	return stmts
+}
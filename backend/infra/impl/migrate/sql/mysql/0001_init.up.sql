@@ -1,8 +1,4 @@
-CREATE DATABASE todolist charset=utf8mb4;
-
-USE todolist;
-
-CREATE TABLE `user`(
+CREATE TABLE IF NOT EXISTS `user`(
     `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT 'Primary Key ID',
     `name` VARCHAR(255) NOT NULL COMMENT 'User Nickname',
     `unique_name` VARCHAR(255) NOT NULL COMMENT 'User Unique Name',
@@ -16,7 +12,7 @@ CREATE TABLE `user`(
     UNIQUE KEY `idx_unique_name` (`unique_name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='User Table';
 
-CREATE TABLE `task` (
+CREATE TABLE IF NOT EXISTS `task` (
     `id` BIGINT NOT NULL AUTO_INCREMENT COMMENT 'Primary Key ID',
     `content` TEXT COMMENT 'Task Content',
     `user_id` BIGINT NOT NULL COMMENT 'Associated User ID',
@@ -28,4 +24,4 @@ CREATE TABLE `task` (
     `deleted_at` BIGINT NULL DEFAULT NULL COMMENT 'Deletion Time (Milliseconds)',
     PRIMARY KEY (`id`),
     INDEX `idx_task_user_id` (`user_id`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Task Table';
\ No newline at end of file
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='Task Table';
@@ -0,0 +1,3 @@
+ALTER TABLE `user`
+    DROP COLUMN `disabled_at`,
+    DROP COLUMN `role`;
@@ -0,0 +1,3 @@
+ALTER TABLE `user`
+    ADD COLUMN `role` VARCHAR(32) NOT NULL DEFAULT 'user' COMMENT 'User Role' AFTER `icon_uri`,
+    ADD COLUMN `disabled_at` BIGINT NULL DEFAULT NULL COMMENT 'Disable Time (Milliseconds)' AFTER `role`;
@@ -0,0 +1,5 @@
+-- Emails are stored lowercased from now on. Fails if existing accounts differ
+-- only in case, those have to be merged by hand first.
+UPDATE `user` SET `email` = LOWER(TRIM(`email`));
+
+ALTER TABLE `user` ADD UNIQUE KEY `idx_email` (`email`);
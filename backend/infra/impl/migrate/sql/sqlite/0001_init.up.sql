@@ -0,0 +1,30 @@
+-- MySQL ENUM columns become TEXT with a CHECK constraint.
+
+CREATE TABLE IF NOT EXISTS `user` (
+    `id` INTEGER NOT NULL PRIMARY KEY,
+    `name` VARCHAR(255) NOT NULL,
+    `unique_name` VARCHAR(255) NOT NULL,
+    `email` VARCHAR(255) NOT NULL,
+    `password` VARCHAR(255) NOT NULL,
+    `icon_uri` VARCHAR(512) NOT NULL,
+    `created_at` BIGINT NOT NULL,
+    `updated_at` BIGINT NOT NULL,
+    `deleted_at` BIGINT NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_unique_name` ON `user` (`unique_name`);
+
+CREATE TABLE IF NOT EXISTS `task` (
+    `id` INTEGER NOT NULL PRIMARY KEY,
+    `content` TEXT,
+    `user_id` BIGINT NOT NULL,
+    `due_time` BIGINT NULL DEFAULT NULL,
+    `priority` TEXT NOT NULL DEFAULT 'neither important or urgent'
+        CHECK (`priority` IN ('important and urgent', 'important but not urgent', 'not important but urgent', 'neither important or urgent')),
+    `is_completed` BOOLEAN DEFAULT FALSE,
+    `created_at` BIGINT NOT NULL,
+    `updated_at` BIGINT NOT NULL,
+    `deleted_at` BIGINT NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `idx_task_user_id` ON `task` (`user_id`);
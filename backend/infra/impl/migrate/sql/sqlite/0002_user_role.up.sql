@@ -0,0 +1,3 @@
+ALTER TABLE `user` ADD COLUMN `role` VARCHAR(32) NOT NULL DEFAULT 'user';
+
+ALTER TABLE `user` ADD COLUMN `disabled_at` BIGINT NULL DEFAULT NULL;
@@ -0,0 +1,5 @@
+-- Emails are stored lowercased from now on. Fails if existing accounts differ
+-- only in case, those have to be merged by hand first.
+UPDATE `user` SET `email` = LOWER(TRIM(`email`));
+
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_email` ON `user` (`email`);
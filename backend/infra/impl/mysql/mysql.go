@@ -1,20 +1,114 @@
 package mysql
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 
 	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormlog"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
 )
 
-func New() (*gorm.DB, error) {
-	dsn := conf.GetConf().MySQL.DSN
-	db, err := gorm.Open(mysql.Open(dsn))
+const (
+	defaultMaxOpenConns    = 100
+	defaultMaxIdleConns    = 20
+	defaultConnMaxLifetime = time.Hour
+	defaultConnMaxIdleTime = 10 * time.Minute
+	defaultConnectRetries  = 5
+
+	maxConnectBackoff = 30 * time.Second
+)
+
+// New opens the primary and the replicas in config, ctx bounds the wait for
+// the primary to come up.
+func New(ctx context.Context) (*gorm.DB, error) {
+	c := conf.GetConf().MySQL
+
+	db, err := openWithRetry(ctx, c)
 	if err != nil {
-		return nil, fmt.Errorf("mysql open, dsn: %s, err: %w", dsn, err)
+		return nil, err
+	}
+
+	maxOpen := orDefault(c.MaxOpenConns, defaultMaxOpenConns)
+	maxIdle := orDefault(c.MaxIdleConns, defaultMaxIdleConns)
+	lifetime := orDefault(c.ConnMaxLifetime, defaultConnMaxLifetime)
+	idleTime := orDefault(c.ConnMaxIdleTime, defaultConnMaxIdleTime)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	sqlDB.SetConnMaxLifetime(lifetime)
+	sqlDB.SetConnMaxIdleTime(idleTime)
+
+	if len(c.Replicas) == 0 {
+		return db, nil
+	}
+
+	// Reads go to a random replica, writes and transactions stay on the primary.
+	replicas := make([]gorm.Dialector, 0, len(c.Replicas))
+	for _, dsn := range c.Replicas {
+		replicas = append(replicas, mysql.Open(dsn))
+	}
+
+	err = db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxOpenConns(maxOpen).
+		SetMaxIdleConns(maxIdle).
+		SetConnMaxLifetime(lifetime).
+		SetConnMaxIdleTime(idleTime))
+	if err != nil {
+		return nil, fmt.Errorf("mysql register replicas failed, err: %w", err)
 	}
 
 	return db, nil
 }
+
+// openWithRetry opens the primary, which pings it, retrying with exponential
+// backoff so the service survives starting before the database is ready.
+func openWithRetry(ctx context.Context, c conf.MySQL) (*gorm.DB, error) {
+	retries := orDefault(c.ConnectRetries, defaultConnectRetries)
+	cfg := &gorm.Config{
+		Logger:         gormlog.New(orDefault(c.SlowThreshold, gormlog.DefaultSlowThreshold)),
+		TranslateError: true,
+	}
+
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		db, err := gorm.Open(mysql.Open(c.DSN), cfg)
+		if err == nil {
+			return db, nil
+		}
+
+		if attempt >= retries {
+			return nil, fmt.Errorf("mysql open, dsn: %s, err: %w", conf.RedactDSN(c.DSN), err)
+		}
+
+		logs.CtxWarnf(ctx, "mysql open failed, retry in %s (%d/%d), err: %v", backoff, attempt+1, retries, err)
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("mysql open, dsn: %s, err: %w", conf.RedactDSN(c.DSN), errors.Join(ctx.Err(), err))
+		case <-t.C:
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+func orDefault[T int | time.Duration](v, def T) T {
+	if v > 0 {
+		return v
+	}
+	return def
+}
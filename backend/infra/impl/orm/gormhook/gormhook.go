@@ -0,0 +1,41 @@
+package gormhook
+
+import "gorm.io/gorm"
+
+// Hook is called with the operation, create, query, update, delete, row or
+// raw, of the statement it runs around.
+type Hook func(op string, db *gorm.DB)
+
+// Register adds before and after around every statement gorm runs, named
+// <plugin>:before_<op> and <plugin>:after_<op>.
+func Register(db *gorm.DB, plugin string, before, after Hook) error {
+	type register func(name string, fn func(*gorm.DB)) error
+
+	cb := db.Callback()
+	for _, h := range []struct {
+		op            string
+		before, after register
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	} {
+		if err := h.before(plugin+":before_"+h.op, bind(before, h.op)); err != nil {
+			return err
+		}
+		if err := h.after(plugin+":after_"+h.op, bind(after, h.op)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func bind(h Hook, op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		h(op, db)
+	}
+}
@@ -0,0 +1,72 @@
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// gormLogger routes gorm's logs through pkg/logs, reporting failed statements
+// as errors and statements slower than slowThreshold as warnings.
+type gormLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+func New(slowThreshold time.Duration) logger.Interface {
+	return &gormLogger{level: logger.Warn, slowThreshold: slowThreshold}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	nl := *l
+	nl.level = level
+	return &nl
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		logs.CtxInfof(ctx, "[GORM] "+msg, data...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		logs.CtxWarnf(ctx, "[GORM] "+msg, data...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		logs.CtxErrorf(ctx, "[GORM] "+msg, data...)
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		logs.CtxErrorf(ctx, "[SQL] %s, rows=%d, elapsed=%s, err=%v", sql, rows, elapsed, err)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		logs.CtxWarnf(ctx, "[SlowSQL] %s, rows=%d, elapsed=%s, threshold=%s", sql, rows, elapsed, l.slowThreshold)
+	default:
+		// fc renders the statement, skip it unless it is going to be logged
+		if !logs.Enabled(logs.LevelDebug) {
+			return
+		}
+		sql, rows := fc()
+		logs.CtxDebugf(ctx, "[SQL] %s, rows=%d, elapsed=%s", sql, rows, elapsed)
+	}
+}
@@ -0,0 +1,55 @@
+package gormmetrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormhook"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
+)
+
+const startKey = "gormmetrics:start"
+
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Database statement latency by operation, table and result.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table", "result"})
+
+// Plugin times every statement gorm runs. Register it with db.Use.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "gormmetrics"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	return gormhook.Register(db, "gormmetrics", before, after)
+}
+
+func before(_ string, db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(op string, db *gorm.DB) {
+	v, ok := db.InstanceGet(startKey)
+	if !ok {
+		return
+	}
+	start, ok := v.(time.Time)
+	if !ok {
+		return
+	}
+
+	result := "success"
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		result = "failure"
+	}
+	queryDuration.WithLabelValues(op, db.Statement.Table, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,61 @@
+package gormtracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormhook"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
+)
+
+const spanKey = "gormtracing:span"
+
+// Plugin records a client span for every statement gorm runs, as a child of
+// the span in the statement context. Register it with db.Use.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "gormtracing"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	return gormhook.Register(db, "gormtracing", before, after)
+}
+
+func before(op string, db *gorm.DB) {
+	if db.Statement.Context == nil {
+		return
+	}
+
+	ctx, span := tracing.Start(db.Statement.Context, "db."+op, trace.WithSpanKind(trace.SpanKindClient))
+	db.Statement.Context = ctx
+	db.InstanceSet(spanKey, span)
+}
+
+func after(_ string, db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSystemKey.String(db.Dialector.Name()),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBCollectionName(db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/mysql"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormmetrics"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormtracing"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/sqlite"
+)
+
+// New opens the database selected by database.driver in config.
+func New(ctx context.Context) (*gorm.DB, error) {
+	db, err := open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Use(gormmetrics.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(gormtracing.Plugin{}); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func open(ctx context.Context) (*gorm.DB, error) {
+	driver := conf.GetConf().Database.Driver
+	switch driver {
+	case "", "mysql":
+		return mysql.New(ctx)
+	case "sqlite":
+		return sqlite.New()
+	}
+
+	return nil, fmt.Errorf("unknown database driver: %s", driver)
+}
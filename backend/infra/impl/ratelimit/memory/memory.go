@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/ratelimit"
+)
+
+const sweepInterval = time.Minute
+
+// New returns a Limiter whose buckets only count requests seen by this process.
+// It uses the same GCRA algorithm as the Redis limiter.
+func New() ratelimit.Limiter {
+	return &limiter{tats: make(map[string]time.Time), lastSweep: time.Now()}
+}
+
+type limiter struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time // theoretical arrival time of the next request
+	lastSweep time.Time
+}
+
+func (l *limiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	burst := limit.BurstOrRate()
+	emissionInterval := limit.Period / time.Duration(limit.Rate)
+	burstOffset := emissionInterval * time.Duration(burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	tat, ok := l.tats[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(emissionInterval)
+	diff := now.Sub(newTat.Add(-burstOffset))
+	if diff < 0 {
+		return &ratelimit.Result{
+			Limit:      burst,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}, nil
+	}
+	l.tats[key] = newTat
+
+	return &ratelimit.Result{
+		Allowed:    true,
+		Limit:      burst,
+		Remaining:  int(diff / emissionInterval),
+		ResetAfter: newTat.Sub(now),
+	}, nil
+}
+
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, tat := range l.tats {
+		if tat.Before(now) {
+			delete(l.tats, key)
+		}
+	}
+}
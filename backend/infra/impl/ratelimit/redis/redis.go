@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/ratelimit"
+)
+
+// allowScript implements GCRA, a token bucket that only stores the theoretical
+// arrival time of the next request. Time is read from Redis so that instances
+// with skewed clocks agree. Returns allowed, remaining, retry_after and
+// reset_after, durations in seconds as strings.
+var allowScript = redis.NewScript(`
+local key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local emission_interval = tonumber(ARGV[2])
+local burst_offset = emission_interval * burst
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission_interval
+local diff = now - (new_tat - burst_offset)
+if diff < 0 then
+	return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", key, tostring(new_tat), "PX", math.ceil(reset_after * 1000))
+
+return {1, math.floor(diff / emission_interval), "0", tostring(reset_after)}
+`)
+
+// New returns a Limiter whose buckets live in Redis and are shared by all instances.
+func New(cli redis.Cmdable) ratelimit.Limiter {
+	return &limiter{cli: cli}
+}
+
+type limiter struct {
+	cli redis.Cmdable
+}
+
+func (l *limiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Result, error) {
+	burst := limit.BurstOrRate()
+	emissionInterval := limit.Period.Seconds() / float64(limit.Rate)
+
+	vals, err := allowScript.Run(ctx, l.cli, []string{"ratelimit:" + key}, burst, emissionInterval).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	retryAfter, err := parseSeconds(vals[2])
+	if err != nil {
+		return nil, err
+	}
+	resetAfter, err := parseSeconds(vals[3])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ratelimit.Result{
+		Allowed:    vals[0].(int64) == 1,
+		Limit:      burst,
+		Remaining:  int(vals[1].(int64)),
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+func parseSeconds(v any) (time.Duration, error) {
+	s, _ := v.(string)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(f * float64(time.Second)), nil
+}
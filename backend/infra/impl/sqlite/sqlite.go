@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/conf"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/impl/orm/gormlog"
+)
+
+const (
+	defaultPath = "todolist.db"
+	memoryPath  = ":memory:"
+)
+
+// New opens the SQLite database of database.sqlite.path for local development
+// and hermetic tests. The driver is mattn/go-sqlite3, building needs cgo.
+func New() (*gorm.DB, error) {
+	path := conf.GetConf().Database.SQLite.Path
+	if path == "" {
+		path = defaultPath
+	}
+
+	return Open(path)
+}
+
+// Open opens the SQLite database at path, ":memory:" gives a throwaway
+// in-process database.
+func Open(path string) (*gorm.DB, error) {
+	dsn := path + "?_busy_timeout=5000&_foreign_keys=on"
+	if path != memoryPath {
+		dsn += "&_journal_mode=WAL"
+	}
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         gormlog.New(gormlog.DefaultSlowThreshold),
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open, path: %s, err: %w", path, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serializes writers anyway, and every connection to ":memory:"
+	// would get its own empty database.
+	sqlDB.SetMaxOpenConns(1)
+
+	return db, nil
+}
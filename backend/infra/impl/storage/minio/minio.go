@@ -75,6 +75,19 @@ func (m *minioClient) createBucketIfNeed(ctx context.Context, client *minio.Clie
 	return nil
 }
 
+// Ping checks that the bucket still exists.
+func (m *minioClient) Ping(ctx context.Context) error {
+	exists, err := m.client.BucketExists(ctx, m.bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", m.bucketName)
+	}
+
+	return nil
+}
+
 func (m *minioClient) test() {
 	ctx := context.Background()
 	objectName := fmt.Sprintf("test-file-%d.txt", rand.Int())
@@ -0,0 +1,54 @@
+package storagemetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
+)
+
+var opDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: metrics.Namespace,
+	Subsystem: "storage",
+	Name:      "operation_duration_seconds",
+	Help:      "Object storage operation latency by operation and result.",
+	Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+}, []string{"operation", "result"})
+
+// New wraps s so that every operation is timed. Wrap the backend directly so
+// that cached URL lookups are not counted as storage calls.
+func New(s storage.Storage) storage.Storage {
+	return &timedStorage{s: s}
+}
+
+type timedStorage struct {
+	s storage.Storage
+}
+
+func (t *timedStorage) PutObject(ctx context.Context, objectKey string, content []byte, opts ...storage.PutOptFn) (err error) {
+	defer observe("put", time.Now(), &err)
+	return t.s.PutObject(ctx, objectKey, content, opts...)
+}
+
+func (t *timedStorage) GetObject(ctx context.Context, objectKey string) (_ []byte, err error) {
+	defer observe("get", time.Now(), &err)
+	return t.s.GetObject(ctx, objectKey)
+}
+
+func (t *timedStorage) DeleteObject(ctx context.Context, objectKey string) (err error) {
+	defer observe("delete", time.Now(), &err)
+	return t.s.DeleteObject(ctx, objectKey)
+}
+
+func (t *timedStorage) GetObjectUrl(ctx context.Context, objectKey string, opts ...storage.GetOptFn) (_ string, err error) {
+	defer observe("get_url", time.Now(), &err)
+	return t.s.GetObjectUrl(ctx, objectKey, opts...)
+}
+
+func observe(op string, start time.Time, err *error) {
+	opDuration.WithLabelValues(op, metrics.Result(*err)).Observe(time.Since(start).Seconds())
+}
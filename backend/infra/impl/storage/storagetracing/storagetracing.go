@@ -0,0 +1,56 @@
+package storagetracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
+)
+
+// New wraps s so that every operation is recorded as a client span.
+func New(s storage.Storage) storage.Storage {
+	return &tracedStorage{s: s}
+}
+
+type tracedStorage struct {
+	s storage.Storage
+}
+
+func (t *tracedStorage) PutObject(ctx context.Context, objectKey string, content []byte, opts ...storage.PutOptFn) (err error) {
+	ctx, span := start(ctx, "storage.PutObject", objectKey)
+	defer tracing.End(span, &err)
+
+	span.SetAttributes(attribute.Int("storage.object_size", len(content)))
+	return t.s.PutObject(ctx, objectKey, content, opts...)
+}
+
+func (t *tracedStorage) GetObject(ctx context.Context, objectKey string) (_ []byte, err error) {
+	ctx, span := start(ctx, "storage.GetObject", objectKey)
+	defer tracing.End(span, &err)
+
+	return t.s.GetObject(ctx, objectKey)
+}
+
+func (t *tracedStorage) DeleteObject(ctx context.Context, objectKey string) (err error) {
+	ctx, span := start(ctx, "storage.DeleteObject", objectKey)
+	defer tracing.End(span, &err)
+
+	return t.s.DeleteObject(ctx, objectKey)
+}
+
+func (t *tracedStorage) GetObjectUrl(ctx context.Context, objectKey string, opts ...storage.GetOptFn) (_ string, err error) {
+	ctx, span := start(ctx, "storage.GetObjectUrl", objectKey)
+	defer tracing.End(span, &err)
+
+	return t.s.GetObjectUrl(ctx, objectKey, opts...)
+}
+
+func start(ctx context.Context, name, objectKey string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("storage.object_key", objectKey)),
+	)
+}
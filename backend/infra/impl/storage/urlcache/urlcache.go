@@ -0,0 +1,148 @@
+package urlcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/storage"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+const (
+	// defaultExpire must match the default of the wrapped storage implementations.
+	defaultExpire = 3600 * 24 * 7 // seconds
+
+	minRefreshBefore = time.Minute
+	sweepInterval    = time.Minute
+)
+
+// New wraps s so that signed URLs are memoized in process and, when shared is
+// not nil, in a cache that every instance shares. A URL is served from
+// cache until shortly before it expires, so callers always get a link that
+// stays valid for at least a tenth of its lifetime.
+//
+// Signed URLs sign the object key rather than its content, so overwriting or
+// deleting an object does not need to invalidate them.
+func New(s storage.Storage, shared cache.Cache) storage.Storage {
+	return &cachedStorage{
+		Storage:   s,
+		shared:    shared,
+		lastSweep: time.Now(),
+	}
+}
+
+type cachedStorage struct {
+	storage.Storage
+	shared cache.Cache
+
+	local     sync.Map // cache key -> *entry
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+type entry struct {
+	url      string
+	expireAt time.Time
+}
+
+func (c *cachedStorage) GetObjectUrl(ctx context.Context, objectKey string, opts ...storage.GetOptFn) (string, error) {
+	option := storage.GetOption{}
+	for _, opt := range opts {
+		opt(&option)
+	}
+	if option.Expire == 0 {
+		option.Expire = defaultExpire
+	}
+
+	lifetime := time.Duration(option.Expire) * time.Second
+	ttl := lifetime - max(lifetime/10, minRefreshBefore)
+	if ttl <= 0 {
+		return c.Storage.GetObjectUrl(ctx, objectKey, opts...)
+	}
+
+	key := cacheKey(objectKey, option.Expire)
+	now := time.Now()
+
+	if v, ok := c.local.Load(key); ok {
+		e := v.(*entry)
+		if now.Before(e.expireAt) {
+			return e.url, nil
+		}
+		c.local.Delete(key)
+	}
+
+	if c.shared != nil {
+		if e, ok := c.getRemote(ctx, key); ok && now.Before(e.expireAt) {
+			c.storeLocal(key, e)
+			return e.url, nil
+		}
+	}
+
+	url, err := c.Storage.GetObjectUrl(ctx, objectKey, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	e := &entry{url: url, expireAt: now.Add(ttl)}
+	c.storeLocal(key, e)
+
+	if c.shared != nil {
+		val := strconv.FormatInt(e.expireAt.UnixMilli(), 10) + "|" + url
+		if err := c.shared.Set(ctx, key, val, ttl); err != nil {
+			logs.CtxWarnf(ctx, "cache object url failed, key=%s, err=%v", objectKey, err)
+		}
+	}
+
+	return url, nil
+}
+
+func (c *cachedStorage) getRemote(ctx context.Context, key string) (*entry, bool) {
+	val, err := c.shared.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			logs.CtxWarnf(ctx, "get cached object url failed, key=%s, err=%v", key, err)
+		}
+		return nil, false
+	}
+
+	expireAt, url, ok := strings.Cut(val, "|")
+	if !ok {
+		return nil, false
+	}
+	ms, err := strconv.ParseInt(expireAt, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+
+	return &entry{url: url, expireAt: time.UnixMilli(ms)}, true
+}
+
+func (c *cachedStorage) storeLocal(key string, e *entry) {
+	c.local.Store(key, e)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastSweep) < sweepInterval {
+		return
+	}
+	c.lastSweep = now
+
+	c.local.Range(func(k, v any) bool {
+		if !now.Before(v.(*entry).expireAt) {
+			c.local.Delete(k)
+		}
+		return true
+	})
+}
+
+func cacheKey(objectKey string, expire int64) string {
+	return fmt.Sprintf("storage:object_url:%d:%s", expire, objectKey)
+}
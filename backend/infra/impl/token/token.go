@@ -6,26 +6,33 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/redis/go-redis/v9"
 
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
 	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/token"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/metrics"
 )
 
 type JWT = token.JWT
 
+// revokeCheckTimeout bounds the revocation lookup done for every
+// authenticated request.
+const revokeCheckTimeout = 100 * time.Millisecond
+
 type jwtImpl struct {
-	cmd       redis.Cmdable
+	cache     cache.Cache
 	signAlgo  string
 	secretKey []byte
 }
 
-func New(cmd redis.Cmdable, signAlgo string, secret string) token.JWT {
-	return &jwtImpl{cmd: cmd, signAlgo: signAlgo, secretKey: []byte(secret)}
+func New(c cache.Cache, signAlgo string, secret string) token.JWT {
+	return &jwtImpl{cache: c, signAlgo: signAlgo, secretKey: []byte(secret)}
 }
 
 func (s *jwtImpl) GenerateToken(uid int64, ua string) ([]string, error) {
@@ -41,10 +48,10 @@ func (s *jwtImpl) GenerateToken(uid int64, ua string) ([]string, error) {
 	}
 	res[1] = refresh
 
-	// set refresh in redis
+	// remember refresh so it can be revoked
 	key := tokenKey(uid, ua)
 
-	err = s.cmd.Set(context.Background(), key, refresh, time.Hour*24*30).Err()
+	err = s.cache.Set(context.Background(), key, refresh, time.Hour*24*30)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +74,7 @@ func (s *jwtImpl) newToken(uid int64, duration time.Duration) (string, error) {
 	return str, err
 }
 
-func (s *jwtImpl) ParseToken(tk string) (*token.Claims, error) {
+func (s *jwtImpl) ParseToken(ctx context.Context, tk string) (*token.Claims, error) {
 	t, err := jwt.ParseWithClaims(tk, &token.Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return s.secretKey, nil
 	})
@@ -76,20 +83,57 @@ func (s *jwtImpl) ParseToken(tk string) (*token.Claims, error) {
 	}
 
 	claims, ok := t.Claims.(*token.Claims)
-	if ok {
-		return claims, nil
+	if !ok {
+		return nil, errors.New("jwt is invalid")
+	}
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// checkRevoked rejects tokens issued before the last RevokeAll of their user.
+// Issue times have second precision, so tokens issued in the second of the
+// revocation are rejected with it, even those issued just after it.
+//
+// It fails open when the cache is unavailable: access tokens expire within
+// minutes, and refreshing needs the cache anyway, so an outage lets revoked
+// sessions live on for at most one access token rather than logging out
+// every user. Failures are counted so that an outage is noticed.
+func (s *jwtImpl) checkRevoked(ctx context.Context, claims *token.Claims) error {
+	ctx, cancel := context.WithTimeout(ctx, revokeCheckTimeout)
+	defer cancel()
+
+	res, err := s.cache.Get(ctx, revokedKey(claims.UserID))
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		metrics.TokenRevocationCheckFailures.Inc()
+		logs.CtxWarnf(ctx, "check token revocation failed, accepting the token, uid=%d, err=%v", claims.UserID, err)
+		return nil
 	}
 
-	return nil, errors.New("jwt is invalid")
+	revokedAt, err := strconv.ParseInt(res, 10, 64)
+	if err != nil {
+		return err
+	}
+	issat, err := claims.GetIssuedAt()
+	if err != nil || issat == nil || issat.Unix() <= revokedAt {
+		return errors.New("jwt revoked")
+	}
+
+	return nil
 }
 
-func (s *jwtImpl) TryRefresh(refresh string, ua string) ([]string, *token.Claims, error) {
-	refreshClaims, err := s.ParseToken(refresh)
+func (s *jwtImpl) TryRefresh(ctx context.Context, refresh string, ua string) ([]string, *token.Claims, error) {
+	refreshClaims, err := s.ParseToken(ctx, refresh)
 	if err != nil {
 		return nil, nil, fmt.Errorf("invalid refresh jwt")
 	}
 
-	res, err := s.cmd.Get(context.Background(), tokenKey(refreshClaims.UserID, ua)).Result()
+	res, err := s.cache.Get(ctx, tokenKey(refreshClaims.UserID, ua))
 	if err != nil || res != refresh {
 		return nil, nil, errors.New("jwt invalid or revoked")
 	}
@@ -102,20 +146,30 @@ func (s *jwtImpl) TryRefresh(refresh string, ua string) ([]string, *token.Claims
 	now := time.Now()
 	issat, _ := refreshClaims.GetIssuedAt()
 	expire, _ := refreshClaims.GetExpirationTime()
-	if expire.Sub(now) < expire.Sub(issat.Time)/3 {
+	rotated := expire.Sub(now) < expire.Sub(issat.Time)/3
+	if rotated {
 		// try refresh
 		refresh, err = s.newToken(refreshClaims.UserID, time.Hour*24*30)
-		err = s.cmd.Set(context.Background(), tokenKey(refreshClaims.UserID, ua), refresh, time.Hour*24*30).Err()
+		err = s.cache.Set(ctx, tokenKey(refreshClaims.UserID, ua), refresh, time.Hour*24*30)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
+	metrics.TokenRefreshes.WithLabelValues(strconv.FormatBool(rotated)).Inc()
+
 	return []string{access, refresh}, refreshClaims, nil
 }
 
 func (s *jwtImpl) CleanToken(ctx context.Context, uid int64, ua string) error {
-	return s.cmd.Del(ctx, tokenKey(uid, ua)).Err()
+	return s.cache.Del(ctx, tokenKey(uid, ua))
+}
+
+// RevokeAll remembers when the tokens of uid were revoked for as long as a
+// refresh token lives, the refresh tokens themselves are keyed by user agent
+// and cannot be listed.
+func (s *jwtImpl) RevokeAll(ctx context.Context, uid int64) error {
+	return s.cache.Set(ctx, revokedKey(uid), strconv.FormatInt(time.Now().Unix(), 10), time.Hour*24*30)
 }
 
 func (s *jwtImpl) GetAccessToken(c *gin.Context) (string, error) {
@@ -141,6 +195,10 @@ func tokenKey(uid int64, ua string) string {
 	return fmt.Sprintf("refresh_token:%d:%s", uid, hash)
 }
 
+func revokedKey(uid int64) string {
+	return fmt.Sprintf("token_revoked:%d", uid)
+}
+
 func hashUA(ua string) string {
 	sum := sha1.Sum([]byte(ua))
 	return hex.EncodeToString(sum[:])
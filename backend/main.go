@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
 	"syscall"
 	"time"
 
@@ -14,6 +16,13 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if err := cmd.Exec(os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	g := &run.Group{}
 
 	srv, err := cmd.Init()
@@ -30,6 +39,12 @@ func main() {
 		log.Printf("Server is running at http://localhost%s\n", conf.GetConf().Server.Addr)
 		return httpSrv.ListenAndServe()
 	}, func(err error) {
+		// Only a signal means load balancers may still route here, a server
+		// that failed to listen has nothing to drain
+		if errors.Is(err, run.ErrSignal) {
+			cmd.Drain()
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
 		if err := httpSrv.Shutdown(ctx); err != nil {
@@ -37,9 +52,33 @@ func main() {
 		}
 	})
 
+	if addr := conf.GetConf().Server.AdminAddr; addr != "" {
+		adminSrv := &http.Server{
+			Handler: cmd.Admin(),
+			Addr:    addr,
+		}
+
+		g.Add(func() error {
+			log.Printf("Admin server is running at http://%s\n", addr)
+			return adminSrv.ListenAndServe()
+		}, func(err error) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			defer cancel()
+			if err := adminSrv.Shutdown(ctx); err != nil {
+				log.Printf("failed to shutdown admin server: %v", err)
+			}
+		})
+	}
+
 	g.Add(run.SignalHandler(context.Background(), syscall.SIGINT, syscall.SIGTERM))
 
 	if err := g.Run(); err != nil {
 		log.Printf("program interrupted, err:%s", err)
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := cmd.Close(ctx); err != nil {
+		log.Printf("failed to release resources: %v", err)
+	}
 }
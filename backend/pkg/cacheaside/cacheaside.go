@@ -0,0 +1,120 @@
+package cacheaside
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/cache"
+	"github.com/crazyfrankie/ddd-todolist/backend/infra/contract/orm"
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/logs"
+)
+
+// missMarker is cached for keys whose record does not exist, so lookups of
+// unknown IDs don't keep falling through to the database.
+const missMarker = "-"
+
+// loadTimeout bounds a load, it runs detached from the caller that started it
+// because other callers may be waiting for it too.
+const loadTimeout = 10 * time.Second
+
+// Cache implements cache-aside for records of type T. Concurrent misses on the
+// same key are collapsed into one load, and cache failures only cost a trip to
+// the loader, they are never returned to the caller. Records are copied
+// shallowly, T must not hold pointers, slices or maps callers modify.
+type Cache[T any] struct {
+	cache    cache.Cache
+	ttl      time.Duration
+	missTTL  time.Duration
+	notFound error
+	group    singleflight.Group
+}
+
+// New returns a cache whose loaders report missing records with notFound.
+func New[T any](c cache.Cache, ttl, missTTL time.Duration, notFound error) *Cache[T] {
+	return &Cache[T]{
+		cache:    c,
+		ttl:      ttl,
+		missTTL:  missTTL,
+		notFound: notFound,
+	}
+}
+
+// Get returns the record cached under key, calling load on a miss. load reads
+// from the primary: a lagging replica would otherwise keep a stale record
+// cached for a whole TTL.
+func (c *Cache[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (*T, error)) (*T, error) {
+	val, err := c.cache.Get(ctx, key)
+	switch {
+	case err == nil && val == missMarker:
+		return nil, c.notFound
+	case err == nil:
+		res := new(T)
+		uerr := json.Unmarshal([]byte(val), res)
+		if uerr == nil {
+			return res, nil
+		}
+		logs.CtxWarnf(ctx, "unmarshal cached %s failed: %v", key, uerr)
+	case !errors.Is(err, cache.ErrNotFound):
+		logs.CtxWarnf(ctx, "get cached %s failed: %v", key, err)
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		// one caller going away must not fail the load for the others
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		res, err := load(orm.WithPrimary(loadCtx))
+		switch {
+		case errors.Is(err, c.notFound):
+			c.set(loadCtx, key, missMarker, c.missTTL)
+		case err == nil:
+			if b, err := json.Marshal(res); err == nil {
+				c.set(loadCtx, key, string(b), jitter(c.ttl))
+			}
+		}
+		return res, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		if !r.Shared {
+			return r.Val.(*T), nil
+		}
+		// callers may modify what they get, each gets its own copy
+		res := *r.Val.(*T)
+		return &res, nil
+	}
+}
+
+// Del invalidates keys, call it after the write has been committed.
+func (c *Cache[T]) Del(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := c.cache.Del(ctx, keys...); err != nil {
+		logs.CtxWarnf(ctx, "invalidate cache %v failed: %v", keys, err)
+	}
+}
+
+func (c *Cache[T]) set(ctx context.Context, key, val string, ttl time.Duration) {
+	if err := c.cache.Set(ctx, key, val, ttl); err != nil {
+		logs.CtxWarnf(ctx, "set cache %s failed: %v", key, err)
+	}
+}
+
+// jitter spreads expirations over an extra 10% so keys written together don't
+// expire together.
+func jitter(ttl time.Duration) time.Duration {
+	return ttl + rand.N(ttl/10+1)
+}
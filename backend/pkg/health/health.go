@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckFunc reports whether a dependency is reachable.
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of a readiness check.
+type Result struct {
+	Ready    bool
+	Draining bool
+	Checks   map[string]string // check name -> "ok" or "fail"
+	// Errors holds the error of every failed check. It may describe the
+	// internals of a dependency, log it rather than return it to the prober.
+	Errors map[string]error
+}
+
+// Checker runs the readiness checks of the process. Once draining it reports
+// not ready without running them, so load balancers stop routing new
+// requests while in-flight ones finish.
+type Checker struct {
+	timeout  time.Duration
+	names    []string
+	checks   map[string]CheckFunc
+	draining atomic.Bool
+}
+
+// New returns a Checker giving every check at most timeout to answer.
+func New(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+// Add registers a check under name, replacing any check of the same name.
+// Checks must be added before the Checker is used.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+		sort.Strings(c.names)
+	}
+	c.checks[name] = fn
+}
+
+// Drain flips readiness off for good.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Draining reports whether Drain was called.
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Check runs every check concurrently.
+func (c *Checker) Check(ctx context.Context) Result {
+	if c.Draining() {
+		return Result{Draining: true}
+	}
+
+	errs := make([]error, len(c.names))
+	var wg sync.WaitGroup
+	for i, name := range c.names {
+		wg.Add(1)
+		go func(i int, fn CheckFunc) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			errs[i] = fn(ctx)
+		}(i, c.checks[name])
+	}
+	wg.Wait()
+
+	res := Result{Ready: true, Checks: make(map[string]string, len(c.names)), Errors: make(map[string]error)}
+	for i, name := range c.names {
+		if errs[i] != nil {
+			res.Ready = false
+			res.Checks[name] = "fail"
+			res.Errors[name] = errs[i]
+			continue
+		}
+		res.Checks[name] = "ok"
+	}
+
+	return res
+}
@@ -0,0 +1,99 @@
+package imagex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWEBP = "webp"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image dimensions too large")
+)
+
+// Sniff detects the real image format from the payload's magic bytes,
+// ignoring whatever the client claimed in its Content-Type header.
+func Sniff(payload []byte) (string, error) {
+	switch http.DetectContentType(payload) {
+	case "image/jpeg":
+		return FormatJPEG, nil
+	case "image/png":
+		return FormatPNG, nil
+	case "image/gif":
+		return FormatGIF, nil
+	case "image/webp":
+		return FormatWEBP, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+// Decode decodes payload into pixels only, which drops EXIF and any other
+// metadata carried by the original file. Images with more than maxPixels
+// pixels are rejected before the full decode to guard against decompression bombs.
+func Decode(payload []byte, maxPixels int) (image.Image, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image config failed: %w", err)
+	}
+	if maxPixels > 0 && cfg.Width*cfg.Height > maxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode image failed: %w", err)
+	}
+
+	return img, format, nil
+}
+
+// Square center-crops img to a square and scales it to size x size.
+func Square(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	edge := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-edge)/2
+	y0 := b.Min.Y + (b.Dy()-edge)/2
+	crop := image.Rect(x0, y0, x0+edge, y0+edge)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	return dst
+}
+
+// Encode re-encodes img. JPEG sources stay JPEG, everything else is written
+// as PNG so transparency survives. It returns the content, the file extension
+// and the MIME type of the encoded image.
+func Encode(img image.Image, srcFormat string) (content []byte, ext string, mimeType string, err error) {
+	buf := &bytes.Buffer{}
+
+	switch srcFormat {
+	case FormatJPEG:
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: 85})
+		ext, mimeType = "jpg", "image/jpeg"
+	default:
+		err = png.Encode(buf, img)
+		ext, mimeType = "png", "image/png"
+	}
+	if err != nil {
+		return nil, "", "", fmt.Errorf("encode image failed: %w", err)
+	}
+
+	return buf.Bytes(), ext, mimeType, nil
+}
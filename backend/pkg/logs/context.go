@@ -0,0 +1,43 @@
+package logs
+
+import (
+	"context"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/ctxcache"
+	"github.com/crazyfrankie/ddd-todolist/backend/types/consts"
+)
+
+type logIDKey struct{}
+
+type fieldsKey struct{}
+
+// WithLogID returns a context whose log lines carry logID.
+func WithLogID(ctx context.Context, logID string) context.Context {
+	return context.WithValue(ctx, logIDKey{}, logID)
+}
+
+// LogIDFromCtx returns the log ID set by WithLogID.
+func LogIDFromCtx(ctx context.Context) (string, bool) {
+	logID, ok := ctx.Value(logIDKey{}).(string)
+	return logID, ok
+}
+
+// WithFields returns a context whose log lines carry the extra key-value
+// pairs kv, in addition to any fields already on ctx.
+func WithFields(ctx context.Context, kv ...any) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).([]any)
+	fields := make([]any, 0, len(prev)+len(kv))
+	fields = append(fields, prev...)
+	fields = append(fields, kv...)
+
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+func fieldsFromCtx(ctx context.Context) []any {
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+func uidFromCtx(ctx context.Context) (int64, bool) {
+	return ctxcache.Get[int64](ctx, consts.SessionDataKeyInCtx)
+}
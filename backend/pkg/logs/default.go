@@ -6,11 +6,18 @@ import (
 	"io"
 	"log"
 	"os"
+	"sync/atomic"
 )
 
-var logger FullLogger = &defaultLogger{
-	level:  LevelInfo,
-	stdlog: log.New(os.Stderr, "", log.LstdFlags|log.Lshortfile|log.Lmicroseconds),
+var logger FullLogger = newDefaultLogger()
+
+const stdlogFlags = log.LstdFlags | log.Lshortfile | log.Lmicroseconds
+
+func newDefaultLogger() *defaultLogger {
+	l := &defaultLogger{stdlog: log.New(os.Stderr, "", stdlogFlags)}
+	l.level.Store(int32(LevelInfo))
+
+	return l
 }
 
 // SetOutput sets the output of default logs. By default, it is stderr.
@@ -19,12 +26,40 @@ func SetOutput(w io.Writer) {
 }
 
 // SetLevel sets the level of logs below which logs will not be output.
-// The default log level is LevelTrace.
-// Note that this method is not concurrent-safe.
+// The default log level is LevelInfo. It may be called while logging.
 func SetLevel(lv Level) {
 	logger.SetLevel(lv)
 }
 
+// ErrorOutputSetter is implemented by loggers that can copy warnings and
+// errors to a separate writer.
+type ErrorOutputSetter interface {
+	SetErrorOutput(w io.Writer)
+}
+
+// SetErrorOutput copies logs of LevelWarn and above to w, nil stops copying.
+// It is a no-op for loggers that don't implement ErrorOutputSetter.
+func SetErrorOutput(w io.Writer) {
+	if s, ok := logger.(ErrorOutputSetter); ok {
+		s.SetErrorOutput(w)
+	}
+}
+
+// LevelEnabler is implemented by loggers that can tell whether a level is
+// output, so that callers can skip building messages that would be dropped.
+type LevelEnabler interface {
+	Enabled(lv Level) bool
+}
+
+// Enabled reports whether logs of lv are output. It is always true for
+// loggers that don't implement LevelEnabler.
+func Enabled(lv Level) bool {
+	if e, ok := logger.(LevelEnabler); ok {
+		return e.Enabled(lv)
+	}
+	return true
+}
+
 // DefaultLogger return the default logs for kitex.
 func DefaultLogger() FullLogger {
 	return logger
@@ -144,19 +179,39 @@ func CtxTracef(ctx context.Context, format string, v ...interface{}) {
 
 type defaultLogger struct {
 	stdlog *log.Logger
-	level  Level
+	errlog atomic.Pointer[log.Logger] // copy of warnings and above, nil when unset
+	level  atomic.Int32
 }
 
 func (ll *defaultLogger) SetOutput(w io.Writer) {
 	ll.stdlog.SetOutput(w)
 }
 
+func (ll *defaultLogger) SetErrorOutput(w io.Writer) {
+	if w == nil {
+		ll.errlog.Store(nil)
+		return
+	}
+	ll.errlog.Store(log.New(w, "", stdlogFlags))
+}
+
 func (ll *defaultLogger) SetLevel(lv Level) {
-	ll.level = lv
+	ll.level.Store(int32(lv))
+}
+
+func (ll *defaultLogger) Enabled(lv Level) bool {
+	return Level(ll.level.Load()) <= lv
+}
+
+func (ll *defaultLogger) output(lv Level, msg string) {
+	ll.stdlog.Output(5, msg)
+	if errlog := ll.errlog.Load(); errlog != nil && lv >= LevelWarn {
+		errlog.Output(5, msg)
+	}
 }
 
 func (ll *defaultLogger) logf(lv Level, format *string, v ...interface{}) {
-	if ll.level > lv {
+	if Level(ll.level.Load()) > lv {
 		return
 	}
 	msg := lv.toString()
@@ -165,27 +220,33 @@ func (ll *defaultLogger) logf(lv Level, format *string, v ...interface{}) {
 	} else {
 		msg += fmt.Sprint(v...)
 	}
-	ll.stdlog.Output(4, msg)
+	ll.output(lv, msg)
 	if lv == LevelFatal {
 		os.Exit(1)
 	}
 }
 
 func (ll *defaultLogger) logfCtx(ctx context.Context, lv Level, format *string, v ...interface{}) {
-	if ll.level > lv {
+	if Level(ll.level.Load()) > lv {
 		return
 	}
 	msg := lv.toString()
-	logID := ctx.Value("log-id")
-	if logID != nil {
+	if logID, ok := LogIDFromCtx(ctx); ok {
 		msg += fmt.Sprintf("[log-id: %v] ", logID)
 	}
+	if uid, ok := uidFromCtx(ctx); ok {
+		msg += fmt.Sprintf("[uid: %d] ", uid)
+	}
 	if format != nil {
 		msg += fmt.Sprintf(*format, v...)
 	} else {
 		msg += fmt.Sprint(v...)
 	}
-	ll.stdlog.Output(4, msg)
+	fields := fieldsFromCtx(ctx)
+	for i := 0; i+1 < len(fields); i += 2 {
+		msg += fmt.Sprintf(" %v=%v", fields[i], fields[i+1])
+	}
+	ll.output(lv, msg)
 	if lv == LevelFatal {
 		os.Exit(1)
 	}
@@ -0,0 +1,244 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/crazyfrankie/ddd-todolist/backend/pkg/tracing"
+)
+
+// slog levels of Trace, Notice and Fatal, which slog does not define.
+const (
+	slogLevelTrace  = slog.LevelDebug - 4
+	slogLevelNotice = slog.LevelInfo + 2
+	slogLevelFatal  = slog.LevelError + 4
+)
+
+var slogLevels = []slog.Level{
+	slogLevelTrace,
+	slog.LevelDebug,
+	slog.LevelInfo,
+	slogLevelNotice,
+	slog.LevelWarn,
+	slog.LevelError,
+	slogLevelFatal,
+}
+
+var levelNames = map[slog.Level]string{
+	slogLevelTrace:  "trace",
+	slog.LevelDebug: "debug",
+	slog.LevelInfo:  "info",
+	slogLevelNotice: "notice",
+	slog.LevelWarn:  "warn",
+	slog.LevelError: "error",
+	slogLevelFatal:  "fatal",
+}
+
+// NewJSONLogger returns a logger writing one JSON object per line to w. Lines
+// logged with a context carry its log ID, the session user ID, the trace ID
+// and any fields added with WithFields.
+func NewJSONLogger(w io.Writer, lv Level) FullLogger {
+	l := &jsonLogger{level: new(slog.LevelVar)}
+	l.level.Set(toSlogLevel(lv))
+	l.SetOutput(w)
+
+	return l
+}
+
+type jsonLogger struct {
+	level      *slog.LevelVar
+	handler    atomic.Pointer[slog.JSONHandler]
+	errHandler atomic.Pointer[slog.JSONHandler] // copy of warnings and above, nil when unset
+}
+
+func (l *jsonLogger) SetOutput(w io.Writer) {
+	l.handler.Store(l.newHandler(w))
+}
+
+func (l *jsonLogger) SetErrorOutput(w io.Writer) {
+	if w == nil {
+		l.errHandler.Store(nil)
+		return
+	}
+	l.errHandler.Store(l.newHandler(w))
+}
+
+func (l *jsonLogger) newHandler(w io.Writer) *slog.JSONHandler {
+	return slog.NewJSONHandler(w, &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       l.level,
+		ReplaceAttr: replaceAttr,
+	})
+}
+
+func (l *jsonLogger) SetLevel(lv Level) {
+	l.level.Set(toSlogLevel(lv))
+}
+
+func (l *jsonLogger) Enabled(lv Level) bool {
+	return l.level.Level() <= toSlogLevel(lv)
+}
+
+func (l *jsonLogger) log(ctx context.Context, lv Level, format *string, v ...interface{}) {
+	level := toSlogLevel(lv)
+	h := l.handler.Load()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+
+	var msg string
+	if format != nil {
+		msg = fmt.Sprintf(*format, v...)
+	} else {
+		msg = fmt.Sprint(v...)
+	}
+
+	// skip runtime.Callers, log, the method and the package level function
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if ctx != nil {
+		if logID, ok := LogIDFromCtx(ctx); ok {
+			r.AddAttrs(slog.String("log_id", logID))
+		}
+		if uid, ok := uidFromCtx(ctx); ok {
+			r.AddAttrs(slog.Int64("uid", uid))
+		}
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			r.AddAttrs(slog.String("trace_id", traceID))
+		}
+		r.Add(fieldsFromCtx(ctx)...)
+	} else {
+		ctx = context.Background()
+	}
+	_ = h.Handle(ctx, r)
+	if eh := l.errHandler.Load(); eh != nil && lv >= LevelWarn {
+		_ = eh.Handle(ctx, r)
+	}
+
+	if lv == LevelFatal {
+		os.Exit(1)
+	}
+}
+
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.LevelKey:
+		if lv, ok := a.Value.Any().(slog.Level); ok {
+			if name, ok := levelNames[lv]; ok {
+				return slog.String(slog.LevelKey, name)
+			}
+		}
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			return slog.String("caller", filepath.Base(src.File)+":"+strconv.Itoa(src.Line))
+		}
+	}
+
+	return a
+}
+
+func toSlogLevel(lv Level) slog.Level {
+	if lv >= LevelTrace && lv <= LevelFatal {
+		return slogLevels[lv]
+	}
+
+	return slog.LevelInfo
+}
+
+func (l *jsonLogger) Fatal(v ...interface{}) {
+	l.log(nil, LevelFatal, nil, v...)
+}
+
+func (l *jsonLogger) Error(v ...interface{}) {
+	l.log(nil, LevelError, nil, v...)
+}
+
+func (l *jsonLogger) Warn(v ...interface{}) {
+	l.log(nil, LevelWarn, nil, v...)
+}
+
+func (l *jsonLogger) Notice(v ...interface{}) {
+	l.log(nil, LevelNotice, nil, v...)
+}
+
+func (l *jsonLogger) Info(v ...interface{}) {
+	l.log(nil, LevelInfo, nil, v...)
+}
+
+func (l *jsonLogger) Debug(v ...interface{}) {
+	l.log(nil, LevelDebug, nil, v...)
+}
+
+func (l *jsonLogger) Trace(v ...interface{}) {
+	l.log(nil, LevelTrace, nil, v...)
+}
+
+func (l *jsonLogger) Fatalf(format string, v ...interface{}) {
+	l.log(nil, LevelFatal, &format, v...)
+}
+
+func (l *jsonLogger) Errorf(format string, v ...interface{}) {
+	l.log(nil, LevelError, &format, v...)
+}
+
+func (l *jsonLogger) Warnf(format string, v ...interface{}) {
+	l.log(nil, LevelWarn, &format, v...)
+}
+
+func (l *jsonLogger) Noticef(format string, v ...interface{}) {
+	l.log(nil, LevelNotice, &format, v...)
+}
+
+func (l *jsonLogger) Infof(format string, v ...interface{}) {
+	l.log(nil, LevelInfo, &format, v...)
+}
+
+func (l *jsonLogger) Debugf(format string, v ...interface{}) {
+	l.log(nil, LevelDebug, &format, v...)
+}
+
+func (l *jsonLogger) Tracef(format string, v ...interface{}) {
+	l.log(nil, LevelTrace, &format, v...)
+}
+
+func (l *jsonLogger) CtxFatalf(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelFatal, &format, v...)
+}
+
+func (l *jsonLogger) CtxErrorf(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelError, &format, v...)
+}
+
+func (l *jsonLogger) CtxWarnf(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelWarn, &format, v...)
+}
+
+func (l *jsonLogger) CtxNoticef(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelNotice, &format, v...)
+}
+
+func (l *jsonLogger) CtxInfof(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelInfo, &format, v...)
+}
+
+func (l *jsonLogger) CtxDebugf(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelDebug, &format, v...)
+}
+
+func (l *jsonLogger) CtxTracef(ctx context.Context, format string, v ...interface{}) {
+	l.log(ctx, LevelTrace, &format, v...)
+}
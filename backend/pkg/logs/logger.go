@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // FormatLogger is a logs interface that output logs with a format.
@@ -80,6 +81,17 @@ var strs = []string{
 	"[Fatal] ",
 }
 
+// ParseLevel parses a level name such as "info" or "warn", case insensitively.
+func ParseLevel(s string) (Level, error) {
+	for lv, name := range strs {
+		if strings.EqualFold("["+s+"] ", name) {
+			return Level(lv), nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level: %s", s)
+}
+
 func (lv Level) toString() string {
 	if lv >= LevelTrace && lv <= LevelFatal {
 		return strs[lv]
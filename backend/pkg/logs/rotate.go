@@ -0,0 +1,86 @@
+package logs
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// RotateOptions configures a log file that is rotated by size and by time.
+type RotateOptions struct {
+	Filename   string
+	MaxSizeMB  int  // rotate once the file reaches this size, lumberjack defaults to 100
+	MaxBackups int  // rotated files to keep, 0 keeps all
+	MaxAgeDays int  // remove rotated files older than this, 0 keeps them
+	Compress   bool // gzip rotated files
+	// Interval additionally rotates at every multiple of it since local
+	// midnight, e.g. 24h for a file per day. 0 only rotates by size.
+	Interval time.Duration
+}
+
+// NewRotatingFile returns a writer appending to opts.Filename.
+func NewRotatingFile(opts RotateOptions) io.WriteCloser {
+	f := &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   opts.Filename,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+			LocalTime:  true,
+			Compress:   opts.Compress,
+		},
+		stop: make(chan struct{}),
+	}
+	if opts.Interval > 0 {
+		go f.rotateEvery(opts.Interval)
+	}
+
+	return f
+}
+
+type rotatingFile struct {
+	*lumberjack.Logger
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (f *rotatingFile) rotateEvery(interval time.Duration) {
+	for {
+		now := time.Now()
+		next := nextRotation(now, interval)
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-f.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := f.Rotate(); err != nil {
+				Errorf("rotate log file %s failed: %v", f.Filename, err)
+			}
+		}
+	}
+}
+
+// nextRotation returns the first multiple of interval since the local
+// midnight of now that is after now, intervals shorter than a day start over
+// at the next midnight. Truncate would count from the zero time in UTC and
+// rotate daily files at UTC midnight.
+func nextRotation(now time.Time, interval time.Duration) time.Time {
+	y, m, d := now.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	next := midnight.Add((now.Sub(midnight)/interval + 1) * interval)
+
+	if nextMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, now.Location()); interval < 24*time.Hour && next.After(nextMidnight) {
+		return nextMidnight
+	}
+
+	return next
+}
+
+func (f *rotatingFile) Close() error {
+	f.stopOnce.Do(func() { close(f.stop) })
+	return f.Logger.Close()
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Namespace prefixes every metric exported by the service.
+const Namespace = "todolist"
+
+// Business counters, recorded by the application services.
+var (
+	TasksCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "tasks_created_total",
+		Help:      "Tasks created.",
+	})
+
+	TasksCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "tasks_completed_total",
+		Help:      "Tasks marked as completed.",
+	})
+
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result, success or failure.",
+	}, []string{"result"})
+
+	TokenRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "token_refreshes_total",
+		Help:      "Access tokens issued from a refresh token, by whether the refresh token was rotated.",
+	}, []string{"rotated"})
+
+	TokenRevocationCheckFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "token_revocation_check_failures_total",
+		Help:      "Token revocation lookups that failed and let the token through.",
+	})
+)
+
+// Result returns the result label for err.
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
@@ -0,0 +1,379 @@
+// Command gen generates types/errno from the error catalogs in script/errorx.
+//
+// metadata.yaml lays out the codes and lists the business domains, every
+// other <biz>.yaml holds the codes of one domain and becomes types/errno/<biz>.go.
+// Catalogs are validated as a whole before anything is written, with -check
+// nothing is written and the command fails when a generated file is stale.
+//
+//	go generate ./types/errno
+//	go run ./script/errorx/gen -check
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+const generatedHeader = "// Code generated by tool. DO NOT EDIT."
+
+type metadata struct {
+	ErrorCode struct {
+		TotalLength int `yaml:"total_length"`
+		AppLength   int `yaml:"app_length"`
+		BizLength   int `yaml:"biz_length"`
+		SubLength   int `yaml:"sub_length"`
+	} `yaml:"error_code"`
+	App []app `yaml:"app"`
+}
+
+type app struct {
+	Name     string     `yaml:"name"`
+	Code     int64      `yaml:"code"`
+	Business []business `yaml:"business"`
+}
+
+type business struct {
+	Name string `yaml:"name"`
+	Code int64  `yaml:"code"`
+}
+
+type catalog struct {
+	ErrorCode []errorCode `yaml:"error_code"`
+}
+
+type errorCode struct {
+	Name              string            `yaml:"name"`
+	Code              int64             `yaml:"code"`
+	Message           string            `yaml:"message"`
+	Messages          map[string]string `yaml:"messages"` // BCP 47 tag -> message
+	NoAffectStability bool              `yaml:"no_affect_stability"`
+	HTTPStatus        int               `yaml:"http_status"` // status the code is reported with, 400 when empty
+}
+
+// domain is a business domain ready to be rendered.
+type domain struct {
+	App        string
+	Biz        string
+	BizCode    int64
+	ImportPath string
+	Codes      []code
+}
+
+type code struct {
+	errorCode
+	Value    int64 // the code as registered
+	Private  string
+	Messages []localized
+}
+
+type localized struct {
+	Locale  string
+	Message string
+}
+
+var namePattern = regexp.MustCompile(`^Err[A-Z][A-Za-z0-9]*$`)
+
+func main() {
+	biz := flag.String("biz", "*", `business domain to generate, "*" for all`)
+	appName := flag.String("app-name", "todolist", "app in metadata.yaml the codes belong to")
+	importPath := flag.String("import-path", "github.com/crazyfrankie/frx/errorx/code", "import path of the code registry")
+	outputDir := flag.String("output-dir", "types/errno", "directory of the generated files")
+	scriptDir := flag.String("script-dir", "script/errorx", "directory of metadata.yaml and the catalogs")
+	check := flag.Bool("check", false, "only report generated files that are stale, exit 1 if any")
+	flag.Parse()
+
+	domains, err := load(*scriptDir, *appName, *importPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	stale, err := generate(domains, *biz, *outputDir, *check)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *check && len(stale) > 0 {
+		for _, f := range stale {
+			fmt.Fprintf(os.Stderr, "%s is stale\n", f)
+		}
+		fmt.Fprintln(os.Stderr, "run `go generate ./types/errno` and commit the result")
+		os.Exit(1)
+	}
+}
+
+// load reads and validates every catalog, a domain is only valid next to
+// all the others.
+func load(dir, appName, importPath string) ([]*domain, error) {
+	var meta metadata
+	if err := readYAML(filepath.Join(dir, "metadata.yaml"), &meta); err != nil {
+		return nil, err
+	}
+
+	layout := meta.ErrorCode
+	if layout.AppLength+layout.BizLength+layout.SubLength != layout.TotalLength {
+		return nil, fmt.Errorf("metadata.yaml: app, biz and sub lengths do not add up to total_length %d", layout.TotalLength)
+	}
+	appIdx := slices.IndexFunc(meta.App, func(a app) bool { return a.Name == appName })
+	if appIdx < 0 {
+		return nil, fmt.Errorf("metadata.yaml: app %s not found", appName)
+	}
+	a := meta.App[appIdx]
+
+	bizBase := pow10(layout.SubLength)
+	appBase := pow10(layout.BizLength + layout.SubLength)
+
+	var (
+		errs    []error
+		domains []*domain
+		names   = make(map[string]string) // name -> business
+		values  = make(map[int64]string)  // code -> name
+	)
+	fail := func(biz, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s.yaml: %s", biz, fmt.Sprintf(format, args...)))
+	}
+
+	bizOwner := make(map[int64]string) // biz code -> business
+	for _, b := range a.Business {
+		if b.Code <= 0 || b.Code >= pow10(layout.BizLength) {
+			fail("metadata", "business %s code %d does not fit %d digits", b.Name, b.Code, layout.BizLength)
+		}
+		if owner := bizOwner[b.Code]; owner != "" {
+			fail("metadata", "business %s code %d is already used by %s", b.Name, b.Code, owner)
+		}
+		bizOwner[b.Code] = b.Name
+
+		var c catalog
+		path := filepath.Join(dir, b.Name+".yaml")
+		if err := readYAML(path, &c); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		d := &domain{App: a.Name, Biz: b.Name, BizCode: b.Code, ImportPath: importPath}
+		for _, ec := range c.ErrorCode {
+			value := a.Code*appBase + b.Code*bizBase + ec.Code
+
+			switch {
+			case !namePattern.MatchString(ec.Name):
+				fail(b.Name, "name %q must match %s", ec.Name, namePattern)
+			case names[ec.Name] != "":
+				fail(b.Name, "%s is already defined in %s.yaml", ec.Name, names[ec.Name])
+			default:
+				names[ec.Name] = b.Name
+			}
+
+			switch {
+			case ec.Code <= 0:
+				fail(b.Name, "%s: code must be positive", ec.Name)
+			case ec.Code >= bizBase:
+				fail(b.Name, "%s: code %d does not fit %d digits", ec.Name, ec.Code, layout.SubLength)
+			case values[value] != "":
+				fail(b.Name, "%s: code %d is already used by %s", ec.Name, value, values[value])
+			default:
+				values[value] = ec.Name
+			}
+
+			if ec.Message == "" {
+				fail(b.Name, "%s: message is required", ec.Name)
+			}
+			if ec.HTTPStatus != 0 && (ec.HTTPStatus < 400 || ec.HTTPStatus > 599) {
+				fail(b.Name, "%s: http_status %d is not an error status", ec.Name, ec.HTTPStatus)
+			}
+
+			cd := code{errorCode: ec, Value: value, Private: lowerFirst(ec.Name)}
+			for locale, msg := range ec.Messages {
+				if _, err := language.Parse(locale); err != nil {
+					fail(b.Name, "%s: invalid locale %q", ec.Name, locale)
+				}
+				cd.Messages = append(cd.Messages, localized{Locale: locale, Message: msg})
+			}
+			slices.SortFunc(cd.Messages, func(a, b localized) int {
+				return strings.Compare(a.Locale, b.Locale)
+			})
+			d.Codes = append(d.Codes, cd)
+		}
+		domains = append(domains, d)
+	}
+
+	// catalogs of businesses missing from metadata.yaml would never be generated
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		biz := strings.TrimSuffix(filepath.Base(f), ".yaml")
+		listed := slices.ContainsFunc(a.Business, func(b business) bool { return b.Name == biz })
+		if !listed && biz != "metadata" {
+			fail(biz, "business %s is not listed in metadata.yaml", biz)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+// generate renders the selected domains and writes those that changed, with
+// check it only returns the stale files. Stale includes generated files no
+// catalog produces anymore.
+func generate(domains []*domain, biz, outputDir string, check bool) ([]string, error) {
+	var stale []string
+	want := make(map[string]bool)
+	for _, d := range domains {
+		if biz != "*" && biz != d.Biz {
+			continue
+		}
+
+		src, err := render(d)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(outputDir, d.Biz+".go")
+		want[path] = true
+		old, err := os.ReadFile(path)
+		if err == nil && bytes.Equal(old, src) {
+			continue
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		stale = append(stale, path)
+		if !check {
+			if err := os.WriteFile(path, src, 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if biz != "*" {
+		return stale, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(outputDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if want[f] || !isGenerated(f) {
+			continue
+		}
+		stale = append(stale, f)
+		if !check {
+			if err := os.Remove(f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return stale, nil
+}
+
+var tmpl = template.Must(template.New("errno").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`// Code generated by tool. DO NOT EDIT.
+// app: {{.App}}, biz: {{.Biz}}
+
+package errno
+
+import (
+	"{{.ImportPath}}"
+)
+
+const (
+{{- range $i, $c := .Codes}}
+{{- if $i}}
+{{end}}
+	{{$c.Name}}Code = {{$c.Value}}
+	{{$c.Private}}Message = {{quote $c.Message}}
+	{{$c.Private}}NoAffectStability = {{$c.NoAffectStability}}
+{{- end}}
+)
+
+func init() {
+{{range .Codes}}
+	code.Register(
+		{{.Name}}Code,
+		{{.Private}}Message,
+		code.WithAffectStability(!{{.Private}}NoAffectStability),
+	)
+{{- if .HTTPStatus}}
+
+	registerHTTPStatus({{.Name}}Code, {{.HTTPStatus}})
+{{- end}}
+{{- if .Messages}}
+
+	registerLocalizedMessages({{.Name}}Code, map[string]string{
+{{- range .Messages}}
+		{{quote .Locale}}: {{quote .Message}},
+{{- end}}
+	})
+{{- end}}
+{{end}}
+}
+`))
+
+func render(d *domain) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s.go failed, err: %w", d.Biz, err)
+	}
+
+	return src, nil
+}
+
+func readYAML(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+func isGenerated(path string) bool {
+	b, err := os.ReadFile(path)
+	return err == nil && bytes.HasPrefix(b, []byte(generatedHeader))
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func pow10(n int) int64 {
+	v := int64(1)
+	for range n {
+		v *= 10
+	}
+
+	return v
+}
@@ -5,6 +5,8 @@ import "time"
 const (
 	SessionDataKeyInCtx = "session_data_key_in_ctx"
 
+	// Storage environment variables predating the storage config section, they
+	// are still honoured when the TODOLIST_STORAGE_* variables are unset.
 	StorageType   = "STORAGE_TYPE"
 	MinIOAK       = "MINIO_AK"
 	MinIOSK       = "MINIO_SK"
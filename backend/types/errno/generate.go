@@ -0,0 +1,3 @@
+package errno
+
+//go:generate go run ../../script/errorx/gen -script-dir ../../script/errorx -output-dir .
@@ -0,0 +1,16 @@
+package errno
+
+var httpStatuses = make(map[int32]int) // code -> status
+
+// registerHTTPStatus records the status code is reported with over HTTP. The
+// generated files call it from init, only for codes with http_status set.
+func registerHTTPStatus(code int32, status int) {
+	httpStatuses[code] = status
+}
+
+// HTTPStatus returns the status code is reported with, false when its
+// catalog entry leaves it to the caller.
+func HTTPStatus(code int32) (int, bool) {
+	status, ok := httpStatuses[code]
+	return status, ok
+}
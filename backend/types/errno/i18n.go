@@ -0,0 +1,56 @@
+package errno
+
+import (
+	"slices"
+	"sync"
+)
+
+// DefaultLocale is the locale of the messages registered with code.Register.
+const DefaultLocale = "en"
+
+var (
+	localizedMu       sync.RWMutex
+	localizedMessages = make(map[int32]map[string]string) // code -> locale -> message
+)
+
+// registerLocalizedMessages adds translations of the message of code, keyed
+// by BCP 47 language tag. The generated files call it from init.
+func registerLocalizedMessages(code int32, messages map[string]string) {
+	localizedMu.Lock()
+	defer localizedMu.Unlock()
+
+	if localizedMessages[code] == nil {
+		localizedMessages[code] = make(map[string]string, len(messages))
+	}
+	for locale, msg := range messages {
+		localizedMessages[code][locale] = msg
+	}
+}
+
+// LocalizedMessage returns the message of code in locale, false when it has
+// not been translated to locale.
+func LocalizedMessage(code int32, locale string) (string, bool) {
+	localizedMu.RLock()
+	defer localizedMu.RUnlock()
+
+	msg, ok := localizedMessages[code][locale]
+	return msg, ok
+}
+
+// Locales lists the locales messages are available in, DefaultLocale first.
+func Locales() []string {
+	localizedMu.RLock()
+	defer localizedMu.RUnlock()
+
+	var locales []string
+	for _, messages := range localizedMessages {
+		for locale := range messages {
+			if locale != DefaultLocale && !slices.Contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+	slices.Sort(locales)
+
+	return append([]string{DefaultLocale}, locales...)
+}
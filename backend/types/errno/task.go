@@ -11,6 +11,10 @@ const (
 	ErrTaskNotFoundCode              = 122001
 	errTaskNotFoundMessage           = "task not found"
 	errTaskNotFoundNoAffectStability = true
+
+	ErrTaskPriorityInvalidCode              = 122002
+	errTaskPriorityInvalidMessage           = "invalid task priority"
+	errTaskPriorityInvalidNoAffectStability = true
 )
 
 func init() {
@@ -21,4 +25,22 @@ func init() {
 		code.WithAffectStability(!errTaskNotFoundNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrTaskNotFoundCode, 404)
+
+	registerLocalizedMessages(ErrTaskNotFoundCode, map[string]string{
+		"zh": "任务不存在",
+	})
+
+	code.Register(
+		ErrTaskPriorityInvalidCode,
+		errTaskPriorityInvalidMessage,
+		code.WithAffectStability(!errTaskPriorityInvalidNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrTaskPriorityInvalidCode, 400)
+
+	registerLocalizedMessages(ErrTaskPriorityInvalidCode, map[string]string{
+		"zh": "任务优先级无效",
+	})
+
 }
@@ -27,6 +27,38 @@ const (
 	ErrEmailOrPasswordIncorrectCode              = 111005
 	errEmailOrPasswordIncorrectMessage           = "email or password is incorrect"
 	errEmailOrPasswordIncorrectNoAffectStability = true
+
+	ErrAvatarInvalidCode              = 111006
+	errAvatarInvalidMessage           = "invalid avatar image"
+	errAvatarInvalidNoAffectStability = true
+
+	ErrAvatarTooLargeCode              = 111007
+	errAvatarTooLargeMessage           = "avatar image too large"
+	errAvatarTooLargeNoAffectStability = true
+
+	ErrRegisterInProgressCode              = 111008
+	errRegisterInProgressMessage           = "registration for this email is in progress, retry later"
+	errRegisterInProgressNoAffectStability = true
+
+	ErrCSRFCheckFailedCode              = 111009
+	errCSRFCheckFailedMessage           = "csrf check failed"
+	errCSRFCheckFailedNoAffectStability = true
+
+	ErrAccountDisabledCode              = 111010
+	errAccountDisabledMessage           = "account is disabled"
+	errAccountDisabledNoAffectStability = true
+
+	ErrPermissionDeniedCode              = 111011
+	errPermissionDeniedMessage           = "permission denied"
+	errPermissionDeniedNoAffectStability = true
+
+	ErrDisableSelfCode              = 111012
+	errDisableSelfMessage           = "cannot disable your own account"
+	errDisableSelfNoAffectStability = true
+
+	ErrEmailInvalidCode              = 111013
+	errEmailInvalidMessage           = "invalid email"
+	errEmailInvalidNoAffectStability = true
 )
 
 func init() {
@@ -37,28 +69,154 @@ func init() {
 		code.WithAffectStability(!errUserNotFoundNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrUserNotFoundCode, 404)
+
+	registerLocalizedMessages(ErrUserNotFoundCode, map[string]string{
+		"zh": "用户不存在",
+	})
+
 	code.Register(
 		ErrEmailExistCode,
 		errEmailExistMessage,
 		code.WithAffectStability(!errEmailExistNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrEmailExistCode, 409)
+
+	registerLocalizedMessages(ErrEmailExistCode, map[string]string{
+		"zh": "邮箱已被注册",
+	})
+
 	code.Register(
 		ErrUniqueNameExistCode,
 		errUniqueNameExistMessage,
 		code.WithAffectStability(!errUniqueNameExistNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrUniqueNameExistCode, 409)
+
+	registerLocalizedMessages(ErrUniqueNameExistCode, map[string]string{
+		"zh": "用户名已存在",
+	})
+
 	code.Register(
 		ErrAuthFailedCode,
 		errAuthFailedMessage,
 		code.WithAffectStability(!errAuthFailedNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrAuthFailedCode, 401)
+
+	registerLocalizedMessages(ErrAuthFailedCode, map[string]string{
+		"zh": "认证失败",
+	})
+
 	code.Register(
 		ErrEmailOrPasswordIncorrectCode,
 		errEmailOrPasswordIncorrectMessage,
 		code.WithAffectStability(!errEmailOrPasswordIncorrectNoAffectStability),
 	)
 
+	registerHTTPStatus(ErrEmailOrPasswordIncorrectCode, 401)
+
+	registerLocalizedMessages(ErrEmailOrPasswordIncorrectCode, map[string]string{
+		"zh": "邮箱或密码错误",
+	})
+
+	code.Register(
+		ErrAvatarInvalidCode,
+		errAvatarInvalidMessage,
+		code.WithAffectStability(!errAvatarInvalidNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrAvatarInvalidCode, 400)
+
+	registerLocalizedMessages(ErrAvatarInvalidCode, map[string]string{
+		"zh": "头像图片无效",
+	})
+
+	code.Register(
+		ErrAvatarTooLargeCode,
+		errAvatarTooLargeMessage,
+		code.WithAffectStability(!errAvatarTooLargeNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrAvatarTooLargeCode, 413)
+
+	registerLocalizedMessages(ErrAvatarTooLargeCode, map[string]string{
+		"zh": "头像图片过大",
+	})
+
+	code.Register(
+		ErrRegisterInProgressCode,
+		errRegisterInProgressMessage,
+		code.WithAffectStability(!errRegisterInProgressNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrRegisterInProgressCode, 409)
+
+	registerLocalizedMessages(ErrRegisterInProgressCode, map[string]string{
+		"zh": "该邮箱正在注册中，请稍后重试",
+	})
+
+	code.Register(
+		ErrCSRFCheckFailedCode,
+		errCSRFCheckFailedMessage,
+		code.WithAffectStability(!errCSRFCheckFailedNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrCSRFCheckFailedCode, 403)
+
+	registerLocalizedMessages(ErrCSRFCheckFailedCode, map[string]string{
+		"zh": "CSRF 校验失败",
+	})
+
+	code.Register(
+		ErrAccountDisabledCode,
+		errAccountDisabledMessage,
+		code.WithAffectStability(!errAccountDisabledNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrAccountDisabledCode, 403)
+
+	registerLocalizedMessages(ErrAccountDisabledCode, map[string]string{
+		"zh": "账号已被禁用",
+	})
+
+	code.Register(
+		ErrPermissionDeniedCode,
+		errPermissionDeniedMessage,
+		code.WithAffectStability(!errPermissionDeniedNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrPermissionDeniedCode, 403)
+
+	registerLocalizedMessages(ErrPermissionDeniedCode, map[string]string{
+		"zh": "无权限执行该操作",
+	})
+
+	code.Register(
+		ErrDisableSelfCode,
+		errDisableSelfMessage,
+		code.WithAffectStability(!errDisableSelfNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrDisableSelfCode, 400)
+
+	registerLocalizedMessages(ErrDisableSelfCode, map[string]string{
+		"zh": "不能禁用自己的账号",
+	})
+
+	code.Register(
+		ErrEmailInvalidCode,
+		errEmailInvalidMessage,
+		code.WithAffectStability(!errEmailInvalidNoAffectStability),
+	)
+
+	registerHTTPStatus(ErrEmailInvalidCode, 400)
+
+	registerLocalizedMessages(ErrEmailInvalidCode, map[string]string{
+		"zh": "邮箱格式不正确",
+	})
+
 }
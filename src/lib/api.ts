@@ -11,9 +11,32 @@ import {
 const API_BASE_URL =
   process.env.NEXT_PUBLIC_API_URL || "http://localhost:8080/api";
 
+// 与后端 httputil.CSRFCookie / CSRFHeader 保持一致
+const CSRF_COOKIE = "todolist_csrf";
+const CSRF_HEADER = "X-CSRF-Token";
+
+// 这些接口返回401不代表access token过期，不触发刷新
+const NO_REFRESH_ENDPOINTS = ["/user/login", "/user/register", "/user/refresh"];
+
+function readCookie(name: string): string | null {
+  if (typeof document === "undefined") {
+    return null;
+  }
+  const prefix = `${name}=`;
+  for (const part of document.cookie.split(";")) {
+    const cookie = part.trim();
+    if (cookie.startsWith(prefix)) {
+      return decodeURIComponent(cookie.slice(prefix.length));
+    }
+  }
+  return null;
+}
+
 class ApiClient {
   private baseURL: string;
   private token: string | null = null;
+  // 正在进行的刷新，并发的401共用同一次刷新
+  private refreshing: Promise<boolean> | null = null;
 
   constructor(baseURL: string) {
     this.baseURL = baseURL;
@@ -25,7 +48,8 @@ class ApiClient {
 
   private async request<T>(
     endpoint: string,
-    options: RequestInit = {}
+    options: RequestInit = {},
+    retried = false
   ): Promise<ApiResponse<T>> {
     const url = `${this.baseURL}${endpoint}`;
 
@@ -44,13 +68,16 @@ class ApiClient {
       credentials: "include", // 包含cookies
     });
 
-    // 检查响应头中的access token
-    const accessToken = response.headers.get("x-access-token");
-    if (accessToken) {
-      this.token = accessToken;
-      if (typeof window !== "undefined") {
-        localStorage.setItem("access_token", accessToken);
-      }
+    this.saveAccessToken(response);
+
+    // access token过期时用refresh cookie换一个新的，再重试一次
+    if (
+      response.status === 401 &&
+      !retried &&
+      !NO_REFRESH_ENDPOINTS.includes(endpoint) &&
+      (await this.refreshAccessToken())
+    ) {
+      return this.request<T>(endpoint, options, true);
     }
 
     if (!response.ok) {
@@ -63,6 +90,47 @@ class ApiClient {
     return response.json();
   }
 
+  // 检查响应头中的access token
+  private saveAccessToken(response: Response) {
+    const accessToken = response.headers.get("x-access-token");
+    if (accessToken) {
+      this.setToken(accessToken);
+    }
+  }
+
+  private refreshAccessToken(): Promise<boolean> {
+    if (!this.refreshing) {
+      this.refreshing = this.doRefresh().finally(() => {
+        this.refreshing = null;
+      });
+    }
+    return this.refreshing;
+  }
+
+  private async doRefresh(): Promise<boolean> {
+    // 刷新接口要求在请求头中回传CSRF cookie
+    const csrfToken = readCookie(CSRF_COOKIE);
+    if (!csrfToken) {
+      return false;
+    }
+
+    try {
+      const response = await fetch(`${this.baseURL}/user/refresh`, {
+        method: "POST",
+        headers: { [CSRF_HEADER]: csrfToken },
+        credentials: "include",
+      });
+      if (!response.ok) {
+        this.clearToken();
+        return false;
+      }
+      this.saveAccessToken(response);
+      return true;
+    } catch {
+      return false;
+    }
+  }
+
   // 用户相关API
   async login(data: LoginRequest): Promise<ApiResponse<User>> {
     const response = await this.request<User>("/user/login", {